@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
+
+type addOptions struct {
+	calendarID  string
+	local       bool
+	dryRun      bool
+	yes         bool
+	duration    time.Duration
+	sendUpdates string
+}
+
+func NewAddCmd() *cobra.Command {
+	opts := &addOptions{}
+	cmd := &cobra.Command{
+		Use:   "add <text>...",
+		Short: "Quick-add an event from text such as \"Lunch with Sato tomorrow 12:00\"",
+		Long: `Quick-add an event from text such as "Lunch with Sato tomorrow 12:00".
+
+By default the text is sent to Google Calendar Quick Add.
+With --local the text is parsed by gali so that the event can be previewed before it is created.
+The local parser understands dates (today, tomorrow, monday, next friday, +3d, 12/24, YYYY-MM-DD),
+times (12:00, 3pm, 10:00-11:30) and durations (30min, 1h).`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			quickAddEvent(opts, strings.Join(args, " "))
+		},
+	}
+	f := cmd.Flags()
+	f.StringVarP(&opts.calendarID, "calendar", "c", "", "Calendar ID to create the event in (default: the profile calendar or primary)")
+	f.BoolVar(&opts.local, "local", false, "Parse the text locally and preview the event before creating it")
+	f.BoolVar(&opts.dryRun, "dry-run", false, "Only preview the locally parsed event (implies --local)")
+	f.BoolVarP(&opts.yes, "yes", "y", false, "Create the locally parsed event without confirmation")
+	f.DurationVar(&opts.duration, "duration", time.Hour, "Event length when the text has no end time or duration (--local)")
+	f.StringVar(&opts.sendUpdates, "send-updates", "all", "Send invitations to attendees (all, external or none)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	AddExportFlags(cmd)
+	return cmd
+}
+
+// newEventFromText builds an event from the result of the local parser
+func newEventFromText(ev *parser.EventText) *calendar.Event {
+	event := &calendar.Event{Summary: ev.Summary}
+	if ev.AllDay {
+		event.Start = &calendar.EventDateTime{Date: ev.Start.Format("2006-01-02")}
+		event.End = &calendar.EventDateTime{Date: ev.End.Format("2006-01-02")}
+		return event
+	}
+	tz := parser.GetLocation().String()
+	event.Start = &calendar.EventDateTime{DateTime: ev.Start.Format(time.RFC3339), TimeZone: tz}
+	event.End = &calendar.EventDateTime{DateTime: ev.End.Format(time.RFC3339), TimeZone: tz}
+	return event
+}
+
+func quickAddEvent(opts *addOptions, text string) {
+	opts.calendarID = calendarOrDefault(opts.calendarID)
+	sendUpdates, err := gcalendar.ParseSendUpdates(opts.sendUpdates)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	renderer := render.NewRenderer()
+	if opts.local || opts.dryRun {
+		ev, err := parser.ParseEventText(text, opts.duration)
+		if err != nil {
+			log.Fatalf("Unable to parse event: %v", err)
+		}
+		event := newEventFromText(ev)
+		renderer.RenderEvents(&calendar.Events{Items: []*calendar.Event{event}}, []string{"DATE_TIME", "SUMMARY", "DURATION"})
+		if opts.dryRun {
+			return
+		}
+		if !opts.yes && !confirm("Create this event?") {
+			return
+		}
+
+		srv, err := gcalendar.GetCalendarService(gcalendar.GetGaliWriteScope()...)
+		if err != nil {
+			log.Fatalf("Unable to retrieve Calendar client: %v", err)
+		}
+		created, err := gcalendar.InsertEvent(srv, opts.calendarID, event, sendUpdates)
+		if err != nil {
+			log.Fatalf("Unable to create event: %v", err)
+		}
+		renderer.SetExporter(getExporter())
+		renderer.RenderEvent(created)
+		renderer.WriteLine(created.HtmlLink)
+		return
+	}
+
+	srv, err := gcalendar.GetCalendarService(gcalendar.GetGaliWriteScope()...)
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	created, err := gcalendar.QuickAddEvent(srv, opts.calendarID, text, sendUpdates)
+	if err != nil {
+		log.Fatalf("Unable to quick-add event: %v", err)
+	}
+	renderer.SetExporter(getExporter())
+	renderer.RenderEvent(created)
+	renderer.WriteLine(created.HtmlLink)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	authcmd "github.com/srz-zumix/gali/cmd/auth"
+)
+
+func NewAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage accounts and authentication",
+	}
+	cmd.AddCommand(authcmd.NewAuthListCmd())
+	cmd.AddCommand(authcmd.NewAuthLoginCmd())
+	cmd.AddCommand(authcmd.NewAuthLogoutCmd())
+	cmd.AddCommand(authcmd.NewAuthRefreshCmd())
+	cmd.AddCommand(authcmd.NewAuthStatusCmd())
+	cmd.AddCommand(authcmd.NewAuthSwitchCmd())
+	cmd.AddCommand(authcmd.NewAuthTokenCmd())
+	return cmd
+}
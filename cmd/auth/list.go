@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/cmd/cmdutil"
+	"github.com/srz-zumix/gali/internal/config"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/render"
+)
+
+func NewAuthListCmd() *cobra.Command {
+	var format string
+	var exportOptions render.ExporterOptions
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List account profiles",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.Load()
+			if err != nil {
+				log.Fatalf("Unable to load config: %v", err)
+			}
+			names := cfg.ProfileNames()
+			current := gcalendar.GetProfileName()
+			profiles := make([]*render.ProfileInfo, 0, len(names)+1)
+			found := false
+			for _, name := range append(names, current) {
+				if name == current {
+					if found {
+						continue
+					}
+					found = true
+				}
+				p := cfg.GetProfile(name)
+				profiles = append(profiles, &render.ProfileInfo{
+					Name:        name,
+					Current:     name == current,
+					Credentials: p.Credentials,
+					Calendar:    p.Calendar,
+					TokenFile:   gcalendar.GetTokenLocation(name),
+					LoggedIn:    gcalendar.HasToken(name),
+				})
+			}
+			renderer := render.NewRenderer()
+			renderer.SetExporter(cmdutil.NewExporter(format, exportOptions))
+			renderer.RenderProfiles(profiles)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	cmdutil.AddExportFlags(cmd, &exportOptions)
+	return cmd
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+)
+
+func NewAuthLoginCmd() *cobra.Command {
+	var noBrowser bool
+	var write bool
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Authorize gali with a Google account",
+		Long: `Authorize gali with a Google account using the OAuth client credentials of the profile.
+
+--no-browser prints a link to open in a browser on any machine and asks for the URL it is redirected to,
+which is useful in SSH sessions.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			scopes := []string{}
+			if write {
+				scopes = gcalendar.GetGaliWriteScope()
+			}
+			if err := gcalendar.Login(noBrowser, scopes...); err != nil {
+				log.Fatalf("Login failed: %v", err)
+			}
+			log.Printf("Logged in to profile %s", gcalendar.GetProfileName())
+		},
+	}
+	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Authorize by copying the link and the redirect URL instead of using a local browser")
+	cmd.Flags().BoolVar(&write, "write", false, "Also grant the permission to create and modify events")
+	return cmd
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+)
+
+func NewAuthLogoutCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Revoke and delete the saved token of the profile",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := gcalendar.Logout(); err != nil {
+				log.Fatalf("Logout failed: %v", err)
+			}
+			log.Printf("Logged out of profile %s", gcalendar.GetProfileName())
+		},
+	}
+	return cmd
+}
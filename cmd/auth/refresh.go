@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+)
+
+func NewAuthRefreshCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Refresh the saved token of the profile",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			tok, err := gcalendar.RefreshToken()
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			log.Printf("Token refreshed, expires at %s", tok.Expiry.Format(time.RFC3339))
+		},
+	}
+	return cmd
+}
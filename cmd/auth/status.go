@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/cmd/cmdutil"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/render"
+)
+
+func NewAuthStatusCmd() *cobra.Command {
+	var format string
+	var exportOptions render.ExporterOptions
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the account, granted scopes and token expiry of the profile",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// The status is not a table, so csv, tsv, markdown and ics cannot represent it
+			switch format {
+			case "", "json", "template":
+			default:
+				log.Fatalf("Invalid output format: %s is not supported by auth status (json, template or empty for text)", format)
+			}
+			exporter := cmdutil.NewExporter(format, exportOptions)
+			status, err := gcalendar.GetAuthStatus()
+			renderer := render.NewRenderer()
+			renderer.SetExporter(exporter)
+			renderer.RenderAuthStatus(status)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "Output format (json, template or empty for text)")
+	cmdutil.AddExportFlags(cmd, &exportOptions)
+	return cmd
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/config"
+)
+
+func NewAuthSwitchCmd() *cobra.Command {
+	var credentials string
+	var calendarID string
+	cmd := &cobra.Command{
+		Use:   "switch <profile>",
+		Short: "Select the account profile used by default",
+		Long: `Select the account profile used by default.
+
+The profile is created if it does not exist. --credentials and --calendar update the settings of the profile.
+--profile or GALI_PROFILE takes precedence over the selected profile.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			if err := config.ValidateProfileName(name); err != nil {
+				log.Fatalf("%v", err)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				log.Fatalf("Unable to load config: %v", err)
+			}
+			p := cfg.GetProfile(name)
+			if cmd.Flags().Changed("credentials") {
+				p.Credentials = credentials
+				if credentials != "" {
+					if p.Credentials, err = filepath.Abs(credentials); err != nil {
+						log.Fatalf("Invalid credentials path: %v", err)
+					}
+				}
+			}
+			if cmd.Flags().Changed("calendar") {
+				p.Calendar = calendarID
+			}
+			if name != config.DefaultProfile || p.Credentials != "" || p.Calendar != "" {
+				cfg.SetProfile(name, p)
+			}
+			cfg.Profile = name
+			if err := cfg.Save(); err != nil {
+				log.Fatalf("Unable to save config: %v", err)
+			}
+			log.Printf("Switched to profile %s", name)
+		},
+	}
+	cmd.Flags().StringVar(&credentials, "credentials", "", "Path of the OAuth client credentials JSON of the profile")
+	cmd.Flags().StringVarP(&calendarID, "calendar", "c", "", "Default calendar ID of the profile")
+	return cmd
+}
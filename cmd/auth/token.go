@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+)
+
+func NewAuthTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Print an access token of the profile for scripts",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			tok, _, err := gcalendar.GetToken()
+			if err != nil {
+				log.Fatalf("Unable to get token: %v", err)
+			}
+			fmt.Println(tok.AccessToken)
+		},
+	}
+	return cmd
+}
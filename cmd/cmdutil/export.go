@@ -0,0 +1,25 @@
+package cmdutil
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/render"
+)
+
+// AddExportFlags adds --template, --template-file and --jq to the command
+func AddExportFlags(cmd *cobra.Command, opts *render.ExporterOptions) {
+	f := cmd.Flags()
+	f.StringVarP(&opts.Template, "template", "t", "", "Format output using a Go template (implies --format template)")
+	f.StringVar(&opts.TemplateFile, "template-file", "", "Read the Go template from a file")
+	f.StringVarP(&opts.JQ, "jq", "q", "", "Filter JSON output using a jq expression (implies --format json)")
+}
+
+// NewExporter returns the exporter for --format and the export flags
+func NewExporter(format string, opts render.ExporterOptions) render.Exporter {
+	exporter, err := render.NewExporter(format, opts)
+	if err != nil {
+		log.Fatalf("Invalid output format: %v", err)
+	}
+	return exporter
+}
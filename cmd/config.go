@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	configcmd "github.com/srz-zumix/gali/cmd/config"
+)
+
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage gali configuration",
+	}
+	cmd.AddCommand(configcmd.NewConfigGetCmd())
+	cmd.AddCommand(configcmd.NewConfigListCmd())
+	cmd.AddCommand(configcmd.NewConfigSetCmd())
+	return cmd
+}
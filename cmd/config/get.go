@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/config"
+)
+
+func NewConfigGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a config setting",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.Load()
+			if err != nil {
+				log.Fatalf("Unable to load config: %v", err)
+			}
+			value, err := cfg.Get(args[0])
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			fmt.Println(value)
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/config"
+)
+
+func NewConfigListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List all config settings",
+		Aliases: []string{"ls"},
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.Load()
+			if err != nil {
+				log.Fatalf("Unable to load config: %v", err)
+			}
+			for _, key := range config.Keys() {
+				value, err := cfg.Get(key)
+				if err != nil {
+					log.Fatalf("%v", err)
+				}
+				fmt.Printf("%s=%s\n", key, value)
+			}
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/config"
+)
+
+func NewConfigSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Update a config setting",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.Load()
+			if err != nil {
+				log.Fatalf("Unable to load config: %v", err)
+			}
+			if err := cfg.Set(args[0], args[1]); err != nil {
+				log.Fatalf("%v", err)
+			}
+			if err := cfg.Save(); err != nil {
+				log.Fatalf("Unable to save config: %v", err)
+			}
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
+
+type createOptions struct {
+	calendarID  string
+	summary     string
+	start       string
+	end         string
+	duration    time.Duration
+	allDay      bool
+	attendees   []string
+	rooms       []string
+	description string
+	location    string
+	visibility  string
+	meet        bool
+	sendUpdates string
+}
+
+func NewCreateCmd() *cobra.Command {
+	opts := &createOptions{}
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create an event",
+		Aliases: []string{"c"},
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			createEvent(opts)
+		},
+	}
+	f := cmd.Flags()
+	f.StringVarP(&opts.calendarID, "calendar", "c", "", "Calendar ID to create the event in (default: the profile calendar or primary)")
+	f.StringVarP(&opts.summary, "summary", "s", "", "Event title")
+	f.StringVar(&opts.start, "start", "", "Start time (RFC3339, YYYY-MM-DD HH:MM or date for all-day events)")
+	f.StringVar(&opts.end, "end", "", "End time (RFC3339, YYYY-MM-DD HH:MM or date for all-day events)")
+	f.DurationVar(&opts.duration, "duration", 30*time.Minute, "Event length when --end is not specified")
+	f.BoolVar(&opts.allDay, "all-day", false, "Create an all-day event (--end is the inclusive last day)")
+	f.StringSliceVarP(&opts.attendees, "attendee", "a", nil, "Attendee email (can be specified multiple times or comma separated)")
+	f.StringSliceVar(&opts.rooms, "room", nil, "Room resource name or email (can be specified multiple times or comma separated)")
+	f.StringVarP(&opts.description, "description", "d", "", "Event description")
+	f.StringVarP(&opts.location, "location", "l", "", "Event location")
+	f.StringVar(&opts.visibility, "visibility", "", "Event visibility (default, public, private or confidential)")
+	f.BoolVar(&opts.meet, "meet", false, "Add a Google Meet conference")
+	f.StringVar(&opts.sendUpdates, "send-updates", "all", "Send invitations to attendees (all, external or none)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	AddExportFlags(cmd)
+	if err := cmd.MarkFlagRequired("summary"); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkFlagRequired("start"); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+// newEventDateTimes builds start and end of an event from --start, --end, --duration and --all-day
+func newEventDateTimes(start, end string, duration time.Duration, allDay bool) (*calendar.EventDateTime, *calendar.EventDateTime, error) {
+	if allDay {
+		startDay, err := parser.ParseDate(start)
+		if err != nil {
+			return nil, nil, err
+		}
+		endDay := startDay
+		if end != "" {
+			endDay, err = parser.ParseDate(end)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		// The end date of an all-day event is exclusive
+		return &calendar.EventDateTime{Date: startDay.Format("2006-01-02")},
+			&calendar.EventDateTime{Date: parser.NextDay(endDay).Format("2006-01-02")}, nil
+	}
+	startTime, err := parser.ParseDateTime(start)
+	if err != nil {
+		return nil, nil, err
+	}
+	endTime := startTime.Add(duration)
+	if end != "" {
+		endTime, err = parser.ParseDateTime(end)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if !endTime.After(startTime) {
+		return nil, nil, fmt.Errorf("end must be after start")
+	}
+	tz := parser.GetLocation().String()
+	return &calendar.EventDateTime{DateTime: startTime.Format(time.RFC3339), TimeZone: tz},
+		&calendar.EventDateTime{DateTime: endTime.Format(time.RFC3339), TimeZone: tz}, nil
+}
+
+// resolveRoomAttendees resolves room names to resource attendees
+func resolveRoomAttendees(rooms []string) []*calendar.EventAttendee {
+	if len(rooms) == 0 {
+		return nil
+	}
+	dsrv, err := gcalendar.GetAdminDirectoryService()
+	if err != nil {
+		log.Fatalf("Unable to create Directory service: %v", err)
+	}
+	resources, err := gcalendar.ListAllCalendarResources(dsrv, "my_customer")
+	if err != nil {
+		log.Fatalf("Unable to retrieve resource calendars: %v", err)
+	}
+	attendees := make([]*calendar.EventAttendee, 0, len(rooms))
+	for _, name := range rooms {
+		room, err := gcalendar.FindCalendarResource(resources, name)
+		if err != nil {
+			log.Fatalf("Unable to resolve room: %v", err)
+		}
+		attendees = append(attendees, &calendar.EventAttendee{
+			Email:       room.ResourceEmail,
+			DisplayName: room.ResourceName,
+			Resource:    true,
+		})
+	}
+	return attendees
+}
+
+func createEvent(opts *createOptions) {
+	opts.calendarID = calendarOrDefault(opts.calendarID)
+	sendUpdates, err := gcalendar.ParseSendUpdates(opts.sendUpdates)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := gcalendar.ValidateVisibility(opts.visibility); err != nil {
+		log.Fatalf("%v", err)
+	}
+	start, end, err := newEventDateTimes(opts.start, opts.end, opts.duration, opts.allDay)
+	if err != nil {
+		log.Fatalf("Invalid event time: %v", err)
+	}
+
+	event := &calendar.Event{
+		Summary:     opts.summary,
+		Description: opts.description,
+		Location:    opts.location,
+		Visibility:  opts.visibility,
+		Start:       start,
+		End:         end,
+	}
+	for _, email := range opts.attendees {
+		if email = strings.TrimSpace(email); email != "" {
+			event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: email})
+		}
+	}
+	event.Attendees = append(event.Attendees, resolveRoomAttendees(opts.rooms)...)
+	if opts.meet {
+		event.ConferenceData, err = gcalendar.NewConferenceCreateRequest()
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	srv, err := gcalendar.GetCalendarService(gcalendar.GetGaliWriteScope()...)
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	created, err := gcalendar.InsertEvent(srv, opts.calendarID, event, sendUpdates)
+	if err != nil {
+		log.Fatalf("Unable to create event: %v", err)
+	}
+
+	renderer := render.NewRenderer()
+	renderer.SetExporter(getExporter())
+	renderer.RenderEvent(created)
+	renderer.WriteLine(created.HtmlLink)
+}
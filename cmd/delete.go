@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
+
+func NewDeleteCmd() *cobra.Command {
+	opts := &modifyOptions{}
+	cmd := &cobra.Command{
+		Use:   "delete <eventId>",
+		Short: "Delete an event",
+		Long: `Delete an event.
+
+With --scope following, the recurring series is ended before the instance.`,
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			changes, _, ok := modifyEvent(opts, args[0], func(srv *calendar.Service, event *calendar.Event, scope gcalendar.ModifyScope) ([]*gcalendar.EventChange, error) {
+				return gcalendar.PlanDelete(srv, opts.calendarID, event, scope)
+			})
+			if ok {
+				renderer := render.NewRenderer()
+				for _, change := range changes {
+					renderer.WriteLine(describeDeleteChange(change, args[0]))
+				}
+			}
+		},
+	}
+	addModifyFlags(cmd, opts)
+	return cmd
+}
+
+// describeDeleteChange describes a change planned by PlanDelete for the instance or event eventID
+func describeDeleteChange(change *gcalendar.EventChange, eventID string) string {
+	switch {
+	case change.Action == gcalendar.ChangeUpdate:
+		return "Ended series " + change.Before.Id + " before " + eventID
+	case change.Before.Id != eventID:
+		return "Deleted series " + change.Before.Id
+	default:
+		return "Deleted " + eventID
+	}
+}
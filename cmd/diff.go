@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
+
+func NewDiffCmd() *cobra.Command {
+	var symmetric bool
+	cmd := &cobra.Command{
+		Use:     "diff <calendarId1> <calendarId2>...",
+		Short:   "Show events in the first calendar that are missing from the others",
+		Aliases: []string{"d"},
+		Args:    cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			diffEvents(symmetric, args...)
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&symmetric, "symmetric", false, "Show events that exist in only one of the calendars")
+	f.StringVar(&since, "since", "", "Start date (RFC3339, YYYY-MM-DD or relative such as today, +3d, monday, next week)")
+	f.StringVar(&until, "until", "", "End date, inclusive (YYYY-MM-DD or relative such as tomorrow, -1w, eom) or exact end time (RFC3339)")
+	f.StringVar(&rangeName, "range", "", "Date range shortcut containing --since or today (day, week, month, quarter or year)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, ics, template or empty for text)")
+	f.StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewEventFieldGetters().Names(), ", ")+")")
+	f.BoolVarP(&showID, "show-id", "i", false, "Show event IDs (for use with edit, move and delete)")
+	f.StringVar(&matchBy, "match-by", string(gcalendar.MatchByID), "Match events across calendars by (id, icaluid or overlap)")
+	f.StringArrayVarP(&refIDs, "ref", "r", nil, "Reference calendar ID(s) for private event completion (can be specified multiple times)")
+	f.StringVar(&building, "building", "", "Building ID to fetch all resource emails as reference calendars")
+	f.BoolVarP(&refMyCals, "ref-mycals", "R", false, "Use all my calendars as reference for private event completion")
+	f.Int64Var(&gcalendar.MaxEvents, "max-events", gcalendar.MaxEvents, "Maximum number of events to fetch per calendar (0 for unlimited)")
+	f.IntVar(&gcalendar.Concurrency, "concurrency", gcalendar.Concurrency, "Number of calendars to fetch in parallel")
+	AddFilterFlags(cmd)
+	AddExportFlags(cmd)
+	AddDebugFlag(cmd)
+	return cmd
+}
+
+func diffEvents(symmetric bool, calendarIDs ...string) {
+	srv, err := gcalendar.GetCalendarService()
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	applyCalendarTimeZone(srv, calendarIDs[0])
+
+	since, until, err = parseDateRange()
+	if err != nil {
+		log.Fatalf("Invalid date format: %v", err)
+	}
+
+	mb, err := gcalendar.ParseMatchBy(matchBy)
+	if err != nil {
+		log.Fatalf("Invalid match-by: %v", err)
+	}
+
+	calendars, err := gcalendar.GetEventSets(srv, since, until, mb, getEventFilter(), calendarIDs...)
+	if err != nil {
+		log.Printf("Warning: some calendars could not be retrieved: %v", err)
+	}
+
+	var diff = &calendar.Events{Items: []*calendar.Event{}}
+	sources := calendars[:1]
+	if symmetric {
+		sources = calendars
+	}
+	for i, cal := range sources {
+		for _, ev := range cal.Items {
+			found := false
+			for j, other := range calendars {
+				if i == j {
+					continue
+				}
+				if other.Contains(ev) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				diff.Items = append(diff.Items, ev)
+			}
+		}
+	}
+
+	gcalendar.SortEventsByStartTime(diff)
+
+	refEventMap, err := gcalendar.GetReferenceMappedEvents(srv, since, until, refIDs, refMyCals, building)
+	if err != nil {
+		log.Fatalf("Unable to retrieve events from ref calendars: %v", err)
+	}
+
+	gcalendar.CompletePrivateEvents(diff, refEventMap)
+
+	renderer := render.NewRenderer()
+	renderer.Debug = debug
+	renderer.ShowID = showID
+	renderer.EventCalendars = gcalendar.GetEventSetCalendars(calendars)
+	renderer.Columns = columns
+	renderer.SetExporter(getExporter())
+	renderer.RenderEventsDefault(diff)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
+	"google.golang.org/api/calendar/v3"
+)
+
+type editOptions struct {
+	modifyOptions
+	summary         string
+	start           string
+	end             string
+	description     string
+	location        string
+	visibility      string
+	addAttendees    []string
+	removeAttendees []string
+	meet            bool
+}
+
+func NewEditCmd() *cobra.Command {
+	opts := &editOptions{}
+	cmd := &cobra.Command{
+		Use:   "edit <eventId>",
+		Short: "Update an event",
+		Long: `Update an event.
+
+Only the specified fields are changed. --start without --end keeps the length of the event.
+With --scope all or following, the time shift of the instance is applied to the recurring series.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			editEvent(cmd.Flags(), opts, args[0])
+		},
+	}
+	f := cmd.Flags()
+	f.StringVarP(&opts.summary, "summary", "s", "", "Event title")
+	f.StringVar(&opts.start, "start", "", "Start time (RFC3339, YYYY-MM-DD HH:MM or date for all-day events)")
+	f.StringVar(&opts.end, "end", "", "End time (RFC3339, YYYY-MM-DD HH:MM or inclusive last day for all-day events)")
+	f.StringVarP(&opts.description, "description", "d", "", "Event description")
+	f.StringVarP(&opts.location, "location", "l", "", "Event location")
+	f.StringVar(&opts.visibility, "visibility", "", "Event visibility (default, public, private or confidential)")
+	f.StringSliceVar(&opts.addAttendees, "add-attendee", nil, "Attendee email to add (can be specified multiple times or comma separated)")
+	f.StringSliceVar(&opts.removeAttendees, "remove-attendee", nil, "Attendee email to remove (can be specified multiple times or comma separated)")
+	f.BoolVar(&opts.meet, "meet", false, "Add a Google Meet conference")
+	addModifyFlags(cmd, &opts.modifyOptions)
+	return cmd
+}
+
+// editEventTimes returns the new start and end of the event from --start and --end
+func editEventTimes(event *calendar.Event, start, end string) (*calendar.EventDateTime, *calendar.EventDateTime, error) {
+	oldStart, oldEnd, err := gcalendar.GetEventTimeRange(event)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event.Start.DateTime == "" {
+		startDay := oldStart
+		if start != "" {
+			if startDay, err = parser.ParseDate(start); err != nil {
+				return nil, nil, err
+			}
+		}
+		days := int(oldEnd.Sub(oldStart).Round(24*time.Hour) / (24 * time.Hour))
+		endDay := startDay.AddDate(0, 0, days)
+		if end != "" {
+			last, err := parser.ParseDate(end)
+			if err != nil {
+				return nil, nil, err
+			}
+			// The end date of an all-day event is exclusive
+			endDay = parser.NextDay(last)
+		}
+		if !endDay.After(startDay) {
+			return nil, nil, fmt.Errorf("end must be after start")
+		}
+		return &calendar.EventDateTime{Date: startDay.Format("2006-01-02")},
+			&calendar.EventDateTime{Date: endDay.Format("2006-01-02")}, nil
+	}
+
+	startTime := oldStart
+	if start != "" {
+		if startTime, err = parser.ParseDateTime(start); err != nil {
+			return nil, nil, err
+		}
+	}
+	endTime := startTime.Add(oldEnd.Sub(oldStart))
+	if end != "" {
+		if endTime, err = parser.ParseDateTime(end); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !endTime.After(startTime) {
+		return nil, nil, fmt.Errorf("end must be after start")
+	}
+	tz := event.Start.TimeZone
+	if tz == "" {
+		tz = parser.GetLocation().String()
+	}
+	return &calendar.EventDateTime{DateTime: startTime.Format(time.RFC3339), TimeZone: tz},
+		&calendar.EventDateTime{DateTime: endTime.Format(time.RFC3339), TimeZone: tz}, nil
+}
+
+// apply changes the fields of the event specified by flags
+func (opts *editOptions) apply(flags *pflag.FlagSet, event *calendar.Event) error {
+	if flags.Changed("summary") {
+		event.Summary = opts.summary
+	}
+	if flags.Changed("description") {
+		event.Description = opts.description
+	}
+	if flags.Changed("location") {
+		event.Location = opts.location
+	}
+	if flags.Changed("visibility") {
+		if err := gcalendar.ValidateVisibility(opts.visibility); err != nil {
+			return err
+		}
+		event.Visibility = opts.visibility
+	}
+	if flags.Changed("start") || flags.Changed("end") {
+		start, end, err := editEventTimes(event, opts.start, opts.end)
+		if err != nil {
+			return fmt.Errorf("invalid event time: %w", err)
+		}
+		event.Start, event.End = start, end
+	}
+	if len(opts.removeAttendees) > 0 {
+		event.Attendees = slices.DeleteFunc(event.Attendees, func(a *calendar.EventAttendee) bool {
+			return slices.ContainsFunc(opts.removeAttendees, func(email string) bool {
+				return strings.EqualFold(strings.TrimSpace(email), a.Email)
+			})
+		})
+	}
+	for _, email := range opts.addAttendees {
+		email = strings.TrimSpace(email)
+		if email == "" || slices.ContainsFunc(event.Attendees, func(a *calendar.EventAttendee) bool {
+			return strings.EqualFold(a.Email, email)
+		}) {
+			continue
+		}
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: email})
+	}
+	if opts.meet && event.ConferenceData == nil {
+		conference, err := gcalendar.NewConferenceCreateRequest()
+		if err != nil {
+			return err
+		}
+		event.ConferenceData = conference
+	}
+	return nil
+}
+
+func editEvent(flags *pflag.FlagSet, opts *editOptions, eventID string) {
+	_, edited, ok := modifyEvent(&opts.modifyOptions, eventID, func(srv *calendar.Service, event *calendar.Event, scope gcalendar.ModifyScope) ([]*gcalendar.EventChange, error) {
+		return gcalendar.PlanUpdate(srv, opts.calendarID, event, scope, func(e *calendar.Event) error {
+			return opts.apply(flags, e)
+		})
+	})
+	if !ok {
+		return
+	}
+	if len(edited) == 0 {
+		log.Printf("Warning: no event was updated")
+		return
+	}
+	renderModifiedEvents(edited)
+}
@@ -2,11 +2,12 @@ package cmd
 
 import (
 	"log"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/srz-zumix/gali/internal/gcalendar"
-	"github.com/srz-zumix/gali/internal/parser"
 	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
 )
 
 func NewEventsCmd() *cobra.Command {
@@ -19,20 +20,26 @@ func NewEventsCmd() *cobra.Command {
 			if len(args) > 0 {
 				calendarID = args[0]
 			} else {
-				calendarID = "primary"
+				calendarID = calendarOrDefault("")
 			}
 			listEvents()
 		},
 	}
 
 	f := cmd.Flags()
-	f.StringVar(&since, "since", "", "Start date (RFC3339 or YYYY-MM-DD)")
-	f.StringVar(&until, "until", "", "End date (RFC3339 or YYYY-MM-DD)")
-	f.StringVar(&format, "format", "", "Output format (json or empty for text)")
-	f.BoolVarP(&showDeclined, "show-declined", "D", false, "Show declined events (yes or no)")
+	f.StringVar(&since, "since", "", "Start date (RFC3339, YYYY-MM-DD or relative such as today, +3d, monday, next week)")
+	f.StringVar(&until, "until", "", "End date, inclusive (YYYY-MM-DD or relative such as tomorrow, -1w, eom) or exact end time (RFC3339)")
+	f.StringVar(&rangeName, "range", "", "Date range shortcut containing --since or today (day, week, month, quarter or year)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, ics, template or empty for text)")
+	f.StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewEventFieldGetters().Names(), ", ")+")")
+	f.BoolVarP(&showID, "show-id", "i", false, "Show event IDs (for use with edit, move and delete)")
 	f.StringArrayVarP(&refIDs, "ref", "r", nil, "Reference calendar ID(s) for private event completion (can be specified multiple times)")
 	f.StringVar(&building, "building", "", "Building ID to fetch all resource emails as reference calendars")
 	f.BoolVarP(&refMyCals, "ref-mycals", "R", false, "Use all my calendars as reference for private event completion")
+	f.Int64Var(&gcalendar.MaxEvents, "max-events", gcalendar.MaxEvents, "Maximum number of events to fetch per calendar (0 for unlimited)")
+	f.IntVar(&gcalendar.Concurrency, "concurrency", gcalendar.Concurrency, "Number of calendars to fetch in parallel")
+	AddFilterFlags(cmd)
+	AddExportFlags(cmd)
 	AddDebugFlag(cmd)
 	return cmd
 }
@@ -42,12 +49,14 @@ func listEvents() {
 	if err != nil {
 		log.Fatalf("Unable to retrieve Calendar client: %v", err)
 	}
+	applyCalendarTimeZone(srv, calendarID)
 
-	since, until, err := parser.ParseSinceUntil(since, until)
+	since, until, err := parseDateRange()
 	if err != nil {
 		log.Fatalf("Invalid date format: %v", err)
 	}
-	mainEvents, err := gcalendar.ListEvents(srv, calendarID, since, until)
+	filter := getEventFilter()
+	mainEvents, err := gcalendar.ListEventsQuery(srv, calendarID, since, until, filter.Query)
 	if err != nil {
 		log.Fatalf("Unable to retrieve events: %v", err)
 	}
@@ -58,9 +67,12 @@ func listEvents() {
 	}
 
 	gcalendar.CompletePrivateEvents(mainEvents, refEventMap)
+	filter.Apply(mainEvents)
 	renderer := render.NewRenderer()
 	renderer.Debug = debug
-	renderer.ShowDeclined = showDeclined
-	renderer.SetExporter(render.GetExporter(format))
+	renderer.ShowID = showID
+	renderer.EventCalendars = gcalendar.GetEventCalendars([]string{calendarID}, []*calendar.Events{mainEvents})
+	renderer.Columns = columns
+	renderer.SetExporter(getExporter())
 	renderer.RenderEventsDefault(mainEvents)
 }
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
+	"github.com/srz-zumix/gali/internal/render"
+)
+
+func NewFreeCmd() *cobra.Command {
+	var duration time.Duration
+	var from string
+	var to string
+	var includeWeekends bool
+	var includePrimary bool
+	cmd := &cobra.Command{
+		Use:   "free [calendarId]...",
+		Short: "Find common free time slots of calendars (your primary calendar when none is given)",
+		Run: func(cmd *cobra.Command, args []string) {
+			findFreeSlots(args, duration, from, to, includeWeekends, includePrimary)
+		},
+	}
+	f := cmd.Flags()
+	f.StringVar(&since, "since", "", "Start date (RFC3339, YYYY-MM-DD or relative such as today, +3d, monday, next week)")
+	f.StringVar(&until, "until", "", "End date, inclusive (YYYY-MM-DD or relative such as tomorrow, -1w, eom) or exact end time (RFC3339)")
+	f.StringVar(&rangeName, "range", "", "Date range shortcut containing --since or today (day, week, month, quarter or year)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	f.StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewTimeSlotFieldGetters().Names(), ", ")+")")
+	f.DurationVar(&duration, "duration", 30*time.Minute, "Minimum length of a free slot")
+	f.StringVar(&from, "from", "09:00", "Start of working hours (HH:MM)")
+	f.StringVar(&to, "to", "18:00", "End of working hours (HH:MM)")
+	f.BoolVar(&includeWeekends, "include-weekends", false, "Include Saturdays and Sundays")
+	f.BoolVar(&includePrimary, "include-primary", false, "Include your primary calendar together with the given calendars")
+	f.StringVar(&building, "building", "", "Building ID to add all resource calendars of the building")
+	AddExportFlags(cmd)
+	AddDebugFlag(cmd)
+	return cmd
+}
+
+func findFreeSlots(calendarIDs []string, duration time.Duration, from, to string, includeWeekends, includePrimary bool) {
+	srv, err := gcalendar.GetCalendarService()
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	applyCalendarTimeZone(srv, "primary")
+
+	since, until, err := parseDateRange()
+	if err != nil {
+		log.Fatalf("Invalid date format: %v", err)
+	}
+	start, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		log.Fatalf("Invalid date format: %v", err)
+	}
+	end, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		log.Fatalf("Invalid date format: %v", err)
+	}
+	dayStart, err := parser.ParseClock(from)
+	if err != nil {
+		log.Fatalf("Invalid working hours: %v", err)
+	}
+	dayEnd, err := parser.ParseClock(to)
+	if err != nil {
+		log.Fatalf("Invalid working hours: %v", err)
+	}
+
+	ids := gcalendar.GetReferenceCalendarIDs(srv, calendarIDs, false, building)
+	if !includePrimary && (len(calendarIDs) > 0 || building != "") && !slices.Contains(calendarIDs, "primary") {
+		ids = slices.DeleteFunc(ids, func(id string) bool { return id == "primary" })
+	}
+	if debug {
+		log.Printf("Querying free/busy of %v", ids)
+	}
+	calendars, err := gcalendar.QueryFreeBusy(srv, ids, since, until)
+	if err != nil {
+		log.Fatalf("Unable to query free/busy: %v", err)
+	}
+
+	busy, err := gcalendar.GetBusyPeriods(calendars)
+	if err != nil {
+		log.Fatalf("Unable to query free/busy: %v", err)
+	}
+	slots, err := gcalendar.FindFreeSlots(busy, start, end, gcalendar.FreeSlotOptions{
+		Duration:     duration,
+		DayStart:     dayStart,
+		DayEnd:       dayEnd,
+		SkipWeekends: !includeWeekends,
+		Location:     parser.GetLocation(),
+	})
+	if err != nil {
+		log.Fatalf("Unable to find free slots: %v", err)
+	}
+
+	renderer := render.NewRenderer()
+	renderer.Columns = columns
+	renderer.SetExporter(getExporter())
+	renderer.RenderTimeSlotsDefault(slots)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
+
+func NewInvitesCmd() *cobra.Command {
+	var noPrompt bool
+	var comment string
+	var sendUpdates string
+	cmd := &cobra.Command{
+		Use:   "invites",
+		Short: "List invitations you have not responded to and respond to them",
+		Long: `List invitations you have not responded to and respond to them.
+
+Events of the primary calendar and the calendars given by --ref or --ref-mycals are searched
+from today to 4 weeks ahead unless --since, --until or --range is specified.
+When run in a terminal, asks for a response to each invitation.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listInvites(!noPrompt, comment, sendUpdates)
+		},
+	}
+	f := cmd.Flags()
+	f.StringVar(&since, "since", "", "Start date (RFC3339, YYYY-MM-DD or relative such as today, +3d, monday, next week)")
+	f.StringVar(&until, "until", "", "End date, inclusive (YYYY-MM-DD or relative such as tomorrow, -1w, eom) or exact end time (RFC3339)")
+	f.StringVar(&rangeName, "range", "", "Date range shortcut containing --since or today (day, week, month, quarter or year)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, ics, template or empty for text)")
+	f.StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewEventFieldGetters().Names(), ", ")+")")
+	f.BoolVarP(&showID, "show-id", "i", false, "Show event IDs (for use with rsvp)")
+	f.StringArrayVarP(&refIDs, "ref", "r", nil, "Additional calendar ID(s) to search for invitations (can be specified multiple times)")
+	f.BoolVarP(&refMyCals, "ref-mycals", "R", false, "Search all my calendars for invitations")
+	f.BoolVar(&noPrompt, "no-prompt", false, "Only list the invitations")
+	f.StringVarP(&comment, "comment", "m", "", "Comment to the organizer added to each response")
+	f.StringVar(&sendUpdates, "send-updates", "all", "Notify the organizer and attendees (all, external or none)")
+	f.Int64Var(&gcalendar.MaxEvents, "max-events", gcalendar.MaxEvents, "Maximum number of events to fetch per calendar (0 for unlimited)")
+	f.IntVar(&gcalendar.Concurrency, "concurrency", gcalendar.Concurrency, "Number of calendars to fetch in parallel")
+	AddFilterFlags(cmd)
+	AddExportFlags(cmd)
+	AddDebugFlag(cmd)
+	return cmd
+}
+
+// promptResponse asks how to respond to the invitation and returns the response status, "" to skip or "quit"
+func promptResponse(event *calendar.Event) string {
+	for {
+		answer := strings.ToLower(prompt(fmt.Sprintf("%s: [a]ccept, [d]ecline, [t]entative, [s]kip or [q]uit? ", event.Summary)))
+		switch answer {
+		case "a", "accept":
+			return "accepted"
+		case "d", "decline":
+			return "declined"
+		case "t", "tentative":
+			return "tentative"
+		case "", "s", "skip":
+			return ""
+		case "q", "quit":
+			return "quit"
+		}
+	}
+}
+
+func listInvites(interactive bool, comment, sendUpdates string) {
+	sendUpdates, err := gcalendar.ParseSendUpdates(sendUpdates)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	srv, err := gcalendar.GetCalendarService()
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	applyCalendarTimeZone(srv, "primary")
+
+	if since == "" && until == "" && rangeName == "" {
+		since, until = "today", "+4w"
+	}
+	since, until, err := parseDateRange()
+	if err != nil {
+		log.Fatalf("Invalid date format: %v", err)
+	}
+
+	filter := getEventFilter()
+	ids := gcalendar.GetReferenceCalendarIDs(srv, refIDs, refMyCals, "")
+	results, err := gcalendar.ListEventsMulti(srv, ids, since, until, filter.Query)
+	if err != nil {
+		log.Printf("Warning: some calendars could not be retrieved: %v", err)
+	}
+	invites := &calendar.Events{}
+	for _, events := range results {
+		if events == nil {
+			continue
+		}
+		for _, event := range events.Items {
+			self := gcalendar.GetSelfAttendee(event)
+			if self != nil && !self.Organizer && self.ResponseStatus == "needsAction" && filter.Match(event) {
+				invites.Items = append(invites.Items, event)
+			}
+		}
+	}
+	gcalendar.SortEventsByStartTime(invites)
+	eventCalendars := gcalendar.GetEventCalendars(ids, results)
+
+	renderer := render.NewRenderer()
+	renderer.Debug = debug
+	renderer.ShowID = showID
+	renderer.EventCalendars = eventCalendars
+	exporter := getExporter()
+	renderer.SetExporter(exporter)
+	headers := columns
+	if len(headers) == 0 {
+		headers = []string{"DATE_TIME", "SUMMARY", "ORGANIZER", "CALENDAR"}
+	}
+	renderer.RenderEvents(invites, headers)
+
+	if !interactive || exporter != nil || !renderer.IO.IsStdinTTY() || len(invites.Items) == 0 {
+		return
+	}
+	writeSrv, err := gcalendar.GetCalendarService(gcalendar.GetGaliWriteScope()...)
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	for _, event := range invites.Items {
+		status := promptResponse(event)
+		if status == "quit" {
+			return
+		}
+		if status == "" {
+			continue
+		}
+		if _, err := gcalendar.RespondToEvent(writeSrv, eventCalendars[event.Id], event, status, comment, sendUpdates); err != nil {
+			log.Printf("Warning: unable to respond to %s: %v", event.Id, err)
+		}
+	}
+}
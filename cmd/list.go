@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"log"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/srz-zumix/gali/internal/gcalendar"
@@ -9,21 +10,23 @@ import (
 )
 
 func NewListCmd() *cobra.Command {
-	var format string
+	var columns []string
 	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List all calendars (calendarList)",
 		Aliases: []string{"ls"},
 		Run: func(cmd *cobra.Command, args []string) {
-			listCalendars(format)
+			listCalendars(columns)
 		},
 	}
 	f := cmd.Flags()
-	f.StringVar(&format, "format", "", "Output format (json or empty for text)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	f.StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewCalendarListFieldGetters().Names(), ", ")+")")
+	AddExportFlags(cmd)
 	return cmd
 }
 
-func listCalendars(format string) {
+func listCalendars(columns []string) {
 	srv, err := gcalendar.GetCalendarService()
 	if err != nil {
 		log.Fatalf("Unable to retrieve Calendar client: %v", err)
@@ -33,6 +36,7 @@ func listCalendars(format string) {
 		log.Fatalf("Unable to retrieve calendar list: %v", err)
 	}
 	renderer := render.NewRenderer()
-	renderer.SetExporter(render.GetExporter(format))
+	renderer.Columns = columns
+	renderer.SetExporter(getExporter())
 	renderer.RenderCalendarListDefault(cl)
 }
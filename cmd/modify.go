@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
+
+// modifyOptions are the flags shared by edit, move and delete
+type modifyOptions struct {
+	calendarID  string
+	scope       string
+	sendUpdates string
+	dryRun      bool
+}
+
+type planFunc func(srv *calendar.Service, event *calendar.Event, scope gcalendar.ModifyScope) ([]*gcalendar.EventChange, error)
+
+func addModifyFlags(cmd *cobra.Command, opts *modifyOptions) {
+	f := cmd.Flags()
+	f.StringVarP(&opts.calendarID, "calendar", "c", "", "Calendar ID of the event (default: the profile calendar or primary)")
+	f.StringVar(&opts.scope, "scope", string(gcalendar.ScopeInstance), "Occurrences of a recurring event to change (instance, following or all)")
+	f.StringVar(&opts.sendUpdates, "send-updates", "all", "Send updates to attendees (all, external or none)")
+	f.BoolVar(&opts.dryRun, "dry-run", false, "Show the changes without applying them")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	AddExportFlags(cmd)
+}
+
+// modifyEvent plans the changes to the event and applies them unless --dry-run is specified.
+// It returns the applied changes and their resulting events, and false on dry run.
+func modifyEvent(opts *modifyOptions, eventID string, plan planFunc) ([]*gcalendar.EventChange, []*calendar.Event, bool) {
+	opts.calendarID = calendarOrDefault(opts.calendarID)
+	scope, err := gcalendar.ParseModifyScope(opts.scope)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	sendUpdates, err := gcalendar.ParseSendUpdates(opts.sendUpdates)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	srv, err := gcalendar.GetCalendarService(gcalendar.GetGaliWriteScope()...)
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	event, err := gcalendar.GetEvent(srv, opts.calendarID, eventID)
+	if err != nil {
+		log.Fatalf("Unable to get event %s: %v", eventID, err)
+	}
+	changes, err := plan(srv, event, scope)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if opts.dryRun {
+		renderer := render.NewRenderer()
+		renderer.SetExporter(getExporter())
+		renderer.RenderEventChanges(changes)
+		return nil, nil, false
+	}
+	results, err := gcalendar.ApplyEventChanges(srv, changes, sendUpdates)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return changes, results, true
+}
+
+// renderModifiedEvents renders the events returned by modifyEvent
+func renderModifiedEvents(events []*calendar.Event) {
+	renderer := render.NewRenderer()
+	renderer.SetExporter(getExporter())
+	renderer.RenderEvents(&calendar.Events{Items: events}, []string{"ID", "DATE_TIME", "SUMMARY", "RECURRENCE"})
+}
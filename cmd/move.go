@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"google.golang.org/api/calendar/v3"
+)
+
+func NewMoveCmd() *cobra.Command {
+	opts := &modifyOptions{}
+	var destination string
+	cmd := &cobra.Command{
+		Use:   "move <eventId>",
+		Short: "Move an event to another calendar",
+		Long: `Move an event to another calendar.
+
+A single instance of a recurring event cannot be moved. Use --scope all to move the series,
+or --scope following to end the series before the instance and continue it in the destination calendar.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, moved, ok := modifyEvent(opts, args[0], func(srv *calendar.Service, event *calendar.Event, scope gcalendar.ModifyScope) ([]*gcalendar.EventChange, error) {
+				return gcalendar.PlanMove(srv, opts.calendarID, event, scope, destination)
+			})
+			if ok {
+				renderModifiedEvents(moved)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&destination, "to", "", "Destination calendar ID")
+	if err := cmd.MarkFlagRequired("to"); err != nil {
+		panic(err)
+	}
+	addModifyFlags(cmd, opts)
+	return cmd
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// prompt prints message to stderr and reads a line from stdin
+func prompt(message string) string {
+	fmt.Fprint(os.Stderr, message) // nolint
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil && answer == "" {
+		return ""
+	}
+	return strings.TrimSpace(answer)
+}
+
+// confirm asks a yes/no question and reports whether the answer is yes
+func confirm(message string) bool {
+	answer := strings.ToLower(prompt(message + " [y/N]: "))
+	return answer == "y" || answer == "yes"
+}
@@ -10,6 +10,7 @@ func NewResCmd() *cobra.Command {
 		Use:   "res",
 		Short: "Resource calendar commands",
 	}
+	cmd.AddCommand(rescmd.NewResFindCmd())
 	cmd.AddCommand(rescmd.NewResListCmd())
 	return cmd
 }
@@ -0,0 +1,84 @@
+package res
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/cmd/cmdutil"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
+	"github.com/srz-zumix/gali/internal/render"
+)
+
+func NewResFindCmd() *cobra.Command {
+	var format string
+	var exportOptions render.ExporterOptions
+	var columns []string
+	var buildingId string
+	var start string
+	var end string
+	var duration time.Duration
+	var capacity int64
+	var features []string
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find resource calendars that are free in the time window",
+		Run: func(cmd *cobra.Command, args []string) {
+			startTime, err := parser.ParseDateTime(start)
+			if err != nil {
+				log.Fatalf("Invalid start: %v", err)
+			}
+			endTime := startTime.Add(duration)
+			if end != "" {
+				endTime, err = parser.ParseDateTime(end)
+				if err != nil {
+					log.Fatalf("Invalid end: %v", err)
+				}
+			}
+			if !endTime.After(startTime) {
+				log.Fatalf("End must be after start")
+			}
+
+			dsrv, err := gcalendar.GetAdminDirectoryService()
+			if err != nil {
+				log.Fatalf("Unable to create Directory service: %v", err)
+			}
+			allItems, err := gcalendar.ListAllCalendarResources(dsrv, "my_customer")
+			if err != nil {
+				log.Fatalf("Unable to retrieve resource calendars: %v", err)
+			}
+			items := gcalendar.FilterCalendarResourcesByBuildingId(allItems, buildingId)
+			items = gcalendar.FilterCalendarResourcesByCapacity(items, capacity)
+			items = gcalendar.FilterCalendarResourcesByFeatures(items, features)
+
+			srv, err := gcalendar.GetCalendarService()
+			if err != nil {
+				log.Fatalf("Unable to retrieve Calendar client: %v", err)
+			}
+			items, err = gcalendar.FilterFreeCalendarResources(srv, items, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+			if err != nil {
+				log.Fatalf("Unable to query free/busy: %v", err)
+			}
+			renderer := render.NewRenderer()
+			renderer.Columns = columns
+			renderer.SetExporter(cmdutil.NewExporter(format, exportOptions))
+			renderer.RenderCalendarResource(items)
+		},
+	}
+	f := cmd.Flags()
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	f.StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewCalendarResourceFieldGetters().Names(), ", ")+")")
+	f.StringVar(&buildingId, "building", "", "Filter by buildingId")
+	f.StringVar(&start, "start", "", "Start of the time window (RFC3339 or YYYY-MM-DD HH:MM)")
+	f.StringVar(&end, "end", "", "End of the time window (RFC3339 or YYYY-MM-DD HH:MM)")
+	f.DurationVar(&duration, "duration", 30*time.Minute, "Length of the time window when --end is not specified")
+	f.Int64Var(&capacity, "capacity", 0, "Minimum capacity")
+	f.StringArrayVar(&features, "feature", nil, "Required feature name (can be specified multiple times)")
+	cmdutil.AddExportFlags(cmd, &exportOptions)
+	if err := cmd.MarkFlagRequired("start"); err != nil {
+		panic(err)
+	}
+	return cmd
+}
@@ -2,14 +2,18 @@ package res
 
 import (
 	"log"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/cmd/cmdutil"
 	"github.com/srz-zumix/gali/internal/gcalendar"
 	"github.com/srz-zumix/gali/internal/render"
 )
 
 func NewResListCmd() *cobra.Command {
 	var format string
+	var exportOptions render.ExporterOptions
+	var columns []string
 	var buildingId string
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -26,11 +30,14 @@ func NewResListCmd() *cobra.Command {
 			}
 			items := gcalendar.FilterCalendarResourcesByBuildingId(allItems, buildingId)
 			renderer := render.NewRenderer()
-			renderer.SetExporter(render.GetExporter(format))
+			renderer.Columns = columns
+			renderer.SetExporter(cmdutil.NewExporter(format, exportOptions))
 			renderer.RenderCalendarResource(items)
 		},
 	}
-	cmd.Flags().StringVar(&format, "format", "", "Output format (json or empty for text)")
+	cmd.Flags().StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewCalendarResourceFieldGetters().Names(), ", ")+")")
 	cmd.Flags().StringVar(&buildingId, "building", "", "Filter by buildingId")
+	cmdutil.AddExportFlags(cmd, &exportOptions)
 	return cmd
 }
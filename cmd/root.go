@@ -13,6 +13,9 @@ var rootCmd = &cobra.Command{
 	Short:   "Google Calendar CLI",
 	Long:    `Google Calendar CLI using Google Calendar API`,
 	Version: version.Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		setup()
+	},
 }
 
 func Execute() {
@@ -23,9 +26,23 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Account profile to use (default: GALI_PROFILE or the profile selected by gali auth switch)")
+	rootCmd.PersistentFlags().StringVar(&impersonate, "impersonate", "", "User email that the service account acts as with domain-wide delegation")
+	rootCmd.PersistentFlags().StringVar(&timeZone, "tz", "", "Timezone for date ranges and displayed times (IANA name or \"calendar\" for the calendar's own timezone)")
+	rootCmd.AddCommand(NewAddCmd())
+	rootCmd.AddCommand(NewAuthCmd())
+	rootCmd.AddCommand(NewConfigCmd())
+	rootCmd.AddCommand(NewCreateCmd())
+	rootCmd.AddCommand(NewDeleteCmd())
+	rootCmd.AddCommand(NewDiffCmd())
+	rootCmd.AddCommand(NewEditCmd())
 	rootCmd.AddCommand(NewEventsCmd())
+	rootCmd.AddCommand(NewFreeCmd())
 	rootCmd.AddCommand(NewIntersectCmd())
+	rootCmd.AddCommand(NewInvitesCmd())
 	rootCmd.AddCommand(NewListCmd())
+	rootCmd.AddCommand(NewMoveCmd())
 	rootCmd.AddCommand(NewResCmd())
+	rootCmd.AddCommand(NewRsvpCmd())
 	rootCmd.AddCommand(NewUnionCmd())
 }
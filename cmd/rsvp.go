@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
+
+type rsvpOptions struct {
+	calendarID  string
+	comment     string
+	sendUpdates string
+	yes         bool
+}
+
+func NewRsvpCmd() *cobra.Command {
+	opts := &rsvpOptions{}
+	cmd := &cobra.Command{
+		Use:   "rsvp [eventId] <accept|decline|tentative>",
+		Short: "Respond to an invitation",
+		Long: `Respond to an invitation.
+
+Without eventId, responds to every invitation in the date range given by --since, --until or --range
+(e.g. decline everything while on vacation) after confirmation. The event filters select the invitations.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.calendarID = calendarOrDefault(opts.calendarID)
+			if len(args) == 2 {
+				rsvpEvent(opts, args[0], args[1])
+				return
+			}
+			if since == "" && until == "" && rangeName == "" {
+				log.Fatalf("eventId or --since, --until or --range is required")
+			}
+			rsvpEvents(opts, args[0])
+		},
+	}
+	f := cmd.Flags()
+	f.StringVarP(&opts.calendarID, "calendar", "c", "", "Calendar ID of the event (default: the profile calendar or primary)")
+	f.StringVarP(&opts.comment, "comment", "m", "", "Comment to the organizer")
+	f.StringVar(&opts.sendUpdates, "send-updates", "all", "Notify the organizer and attendees (all, external or none)")
+	f.BoolVarP(&opts.yes, "yes", "y", false, "Respond to events in the date range without confirmation")
+	f.StringVar(&since, "since", "", "Start date of the bulk mode (RFC3339, YYYY-MM-DD or relative such as today, +3d, monday, next week)")
+	f.StringVar(&until, "until", "", "End date of the bulk mode, inclusive (YYYY-MM-DD or relative such as tomorrow, -1w, eom) or exact end time (RFC3339)")
+	f.StringVar(&rangeName, "range", "", "Date range shortcut of the bulk mode containing --since or today (day, week, month, quarter or year)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, template or empty for text)")
+	AddFilterFlags(cmd)
+	AddExportFlags(cmd)
+	return cmd
+}
+
+// parseRsvpArgs validates the response and --send-updates and returns the calendar service
+func parseRsvpArgs(opts *rsvpOptions, response string) (*calendar.Service, string, string) {
+	status, err := gcalendar.ParseResponse(response)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	sendUpdates, err := gcalendar.ParseSendUpdates(opts.sendUpdates)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	srv, err := gcalendar.GetCalendarService(gcalendar.GetGaliWriteScope()...)
+	if err != nil {
+		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+	}
+	return srv, status, sendUpdates
+}
+
+func renderRsvpEvents(events []*calendar.Event) {
+	renderer := render.NewRenderer()
+	renderer.SetExporter(getExporter())
+	renderer.RenderEvents(&calendar.Events{Items: events}, []string{"ID", "DATE_TIME", "SUMMARY", "RESPONSE_STATUS"})
+}
+
+func rsvpEvent(opts *rsvpOptions, eventID, response string) {
+	srv, status, sendUpdates := parseRsvpArgs(opts, response)
+	event, err := gcalendar.GetEvent(srv, opts.calendarID, eventID)
+	if err != nil {
+		log.Fatalf("Unable to get event %s: %v", eventID, err)
+	}
+	updated, err := gcalendar.RespondToEvent(srv, opts.calendarID, event, status, opts.comment, sendUpdates)
+	if err != nil {
+		log.Fatalf("Unable to respond to event: %v", err)
+	}
+	renderRsvpEvents([]*calendar.Event{updated})
+}
+
+func rsvpEvents(opts *rsvpOptions, response string) {
+	srv, status, sendUpdates := parseRsvpArgs(opts, response)
+	applyCalendarTimeZone(srv, opts.calendarID)
+	since, until, err := parseDateRange()
+	if err != nil {
+		log.Fatalf("Invalid date format: %v", err)
+	}
+	filter := getEventFilter()
+	events, err := gcalendar.ListEventsQuery(srv, opts.calendarID, since, until, filter.Query)
+	if err != nil {
+		log.Fatalf("Unable to retrieve events: %v", err)
+	}
+	filter.Apply(events)
+
+	// Invitations from others whose response differs
+	targets := []*calendar.Event{}
+	for _, event := range events.Items {
+		self := gcalendar.GetSelfAttendee(event)
+		if self == nil || self.Organizer || self.ResponseStatus == status {
+			continue
+		}
+		targets = append(targets, event)
+	}
+	if len(targets) == 0 {
+		log.Printf("No invitations to respond to")
+		return
+	}
+
+	if !opts.yes {
+		renderer := render.NewRenderer()
+		renderer.RenderEvents(&calendar.Events{Items: targets}, []string{"DATE_TIME", "SUMMARY", "ORGANIZER", "RESPONSE_STATUS"})
+		if !confirm(fmt.Sprintf("Set response of %d event(s) to %s?", len(targets), status)) {
+			return
+		}
+	}
+
+	updated := make([]*calendar.Event, 0, len(targets))
+	for _, event := range targets {
+		e, err := gcalendar.RespondToEvent(srv, opts.calendarID, event, status, opts.comment, sendUpdates)
+		if err != nil {
+			log.Printf("Warning: unable to respond to %s: %v", event.Id, err)
+			continue
+		}
+		updated = append(updated, e)
+	}
+	renderRsvpEvents(updated)
+}
@@ -2,11 +2,10 @@ package cmd
 
 import (
 	"log"
-	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/srz-zumix/gali/internal/gcalendar"
-	"github.com/srz-zumix/gali/internal/parser"
 	"github.com/srz-zumix/gali/internal/render"
 	"google.golang.org/api/calendar/v3"
 )
@@ -22,12 +21,20 @@ func NewUnionCmd() *cobra.Command {
 		},
 	}
 	f := cmd.Flags()
-	f.StringVar(&since, "since", "", "Start date (RFC3339 or YYYY-MM-DD)")
-	f.StringVar(&until, "until", "", "End date (RFC3339 or YYYY-MM-DD)")
-	f.StringVar(&format, "format", "", "Output format (json or empty for text)")
+	f.StringVar(&since, "since", "", "Start date (RFC3339, YYYY-MM-DD or relative such as today, +3d, monday, next week)")
+	f.StringVar(&until, "until", "", "End date, inclusive (YYYY-MM-DD or relative such as tomorrow, -1w, eom) or exact end time (RFC3339)")
+	f.StringVar(&rangeName, "range", "", "Date range shortcut containing --since or today (day, week, month, quarter or year)")
+	f.StringVar(&format, "format", "", "Output format (json, csv, tsv, markdown, ics, template or empty for text)")
+	f.StringSliceVar(&columns, "columns", nil, "Columns to display, comma separated ("+strings.Join(render.NewEventFieldGetters().Names(), ", ")+")")
+	f.BoolVarP(&showID, "show-id", "i", false, "Show event IDs (for use with edit, move and delete)")
+	f.StringVar(&matchBy, "match-by", string(gcalendar.MatchByID), "Match events across calendars by (id, icaluid or overlap)")
 	f.StringArrayVarP(&refIDs, "ref", "r", nil, "Reference calendar ID(s) for private event completion (can be specified multiple times)")
 	f.StringVar(&building, "building", "", "Building ID to fetch all resource emails as reference calendars")
 	f.BoolVarP(&refMyCals, "ref-mycals", "R", false, "Use all my calendars as reference for private event completion")
+	f.Int64Var(&gcalendar.MaxEvents, "max-events", gcalendar.MaxEvents, "Maximum number of events to fetch per calendar (0 for unlimited)")
+	f.IntVar(&gcalendar.Concurrency, "concurrency", gcalendar.Concurrency, "Number of calendars to fetch in parallel")
+	AddFilterFlags(cmd)
+	AddExportFlags(cmd)
 	AddDebugFlag(cmd)
 	return cmd
 }
@@ -37,38 +44,39 @@ func unionEvents(calendarIDs ...string) {
 	if err != nil {
 		log.Fatalf("Unable to retrieve Calendar client: %v", err)
 	}
+	applyCalendarTimeZone(srv, calendarIDs[0])
 
-	since, until, err = parser.ParseSinceUntil(since, until)
+	since, until, err = parseDateRange()
 	if err != nil {
 		log.Fatalf("Invalid date format: %v", err)
 	}
 
-	calendars := gcalendar.GetIdMappedEvents(srv, since, until, calendarIDs...)
+	mb, err := gcalendar.ParseMatchBy(matchBy)
+	if err != nil {
+		log.Fatalf("Invalid match-by: %v", err)
+	}
 
-	var union = &calendar.Events{Items: []*calendar.Event{}}
-	var unionMap = map[string]any{}
+	calendars, err := gcalendar.GetEventSets(srv, since, until, mb, getEventFilter(), calendarIDs...)
+	if err != nil {
+		log.Printf("Warning: some calendars could not be retrieved: %v", err)
+	}
 
+	unionSet := gcalendar.NewEventSet(mb)
 	for _, cal := range calendars {
-		for id, ev := range cal {
-			if _, ok := unionMap[id]; !ok {
-				union.Items = append(union.Items, ev)
-				unionMap[id] = nil
+		// Compare with the events of the other calendars only, so that overlapping events of one calendar are all kept
+		added := []*calendar.Event{}
+		for _, ev := range cal.Items {
+			if !unionSet.Contains(ev) {
+				added = append(added, ev)
 			}
 		}
+		for _, ev := range added {
+			unionSet.Add(ev)
+		}
 	}
+	var union = &calendar.Events{Items: unionSet.Items}
 
-	// Sort events by start time
-	sort.Slice(union.Items, func(i, j int) bool {
-		startI := union.Items[i].Start.DateTime
-		if startI == "" {
-			startI = union.Items[i].Start.Date
-		}
-		startJ := union.Items[j].Start.DateTime
-		if startJ == "" {
-			startJ = union.Items[j].Start.Date
-		}
-		return startI < startJ
-	})
+	gcalendar.SortEventsByStartTime(union)
 
 	refEventMap, err := gcalendar.GetReferenceMappedEvents(srv, since, until, refIDs, refMyCals, building)
 	if err != nil {
@@ -79,6 +87,9 @@ func unionEvents(calendarIDs ...string) {
 
 	renderer := render.NewRenderer()
 	renderer.Debug = debug
-	renderer.SetExporter(render.GetExporter(format))
+	renderer.ShowID = showID
+	renderer.EventCalendars = gcalendar.GetEventSetCalendars(calendars)
+	renderer.Columns = columns
+	renderer.SetExporter(getExporter())
 	renderer.RenderEventsDefault(union)
 }
@@ -1,19 +1,81 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gali/cmd/cmdutil"
+	"github.com/srz-zumix/gali/internal/config"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
+	"github.com/srz-zumix/gali/internal/render"
+	"google.golang.org/api/calendar/v3"
+)
 
 var (
 	calendarID   string
 	since        string
 	until        string
+	rangeName    string
 	format       string
+	columns      []string
+	matchBy      string
 	showDeclined bool
+	showID       bool
 	refIDs       []string
 	building     string
 	refMyCals    bool
 	debug        bool
+
+	exportOptions render.ExporterOptions
+
+	timeZone            string
+	useCalendarTimeZone bool
+
+	profile         string
+	defaultCalendar string
+	impersonate     string
+
+	statusFilter     []string
+	organizerFilter  []string
+	attendeeFilter   []string
+	hasMeetFilter    optionalBool
+	allDayFilter     bool
+	timedFilter      bool
+	minDuration      time.Duration
+	visibilityFilter []string
+	queryFilter      string
 )
 
+// optionalBool is a bool flag value that records whether it was specified
+type optionalBool struct {
+	value *bool
+}
+
+func (b *optionalBool) String() string {
+	if b.value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b.value)
+}
+
+func (b *optionalBool) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	b.value = &v
+	return nil
+}
+
+func (b *optionalBool) Type() string {
+	return "bool"
+}
+
 func AddDebugFlag(cmd *cobra.Command) {
 	f := cmd.Flags()
 	f.BoolVar(&debug, "debug", false, "Enable debug mode")
@@ -22,3 +84,141 @@ func AddDebugFlag(cmd *cobra.Command) {
 		panic(err)
 	}
 }
+
+func AddExportFlags(cmd *cobra.Command) {
+	cmdutil.AddExportFlags(cmd, &exportOptions)
+}
+
+func AddFilterFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.BoolVarP(&showDeclined, "show-declined", "D", false, "Show declined events")
+	f.StringSliceVar(&statusFilter, "status", nil, "Show events with your response status, comma separated (accepted, tentative, needsAction or declined)")
+	f.StringSliceVar(&organizerFilter, "organizer", nil, "Show events organized by the email or name (partial match, comma separated)")
+	f.StringSliceVar(&attendeeFilter, "attendee", nil, "Show events with the attendee email or name (partial match, comma separated)")
+	f.Var(&hasMeetFilter, "has-meet", "Show events with a conference link (--has-meet=false for events without)")
+	f.Lookup("has-meet").NoOptDefVal = "true"
+	f.BoolVar(&allDayFilter, "all-day", false, "Show all-day events only")
+	f.BoolVar(&timedFilter, "timed", false, "Show timed events only")
+	f.DurationVar(&minDuration, "min-duration", 0, "Show events at least this long (e.g. 30m)")
+	f.StringSliceVar(&visibilityFilter, "visibility", nil, "Show events with the visibility, comma separated (default, public, private or confidential)")
+	f.StringVar(&queryFilter, "query", "", "Free text search in summary, description, location, attendees and so on")
+}
+
+// getEventFilter returns the filter from the filter flags.
+// Declined events are excluded unless --status or --show-declined is specified.
+func getEventFilter() *gcalendar.EventFilter {
+	filter := &gcalendar.EventFilter{
+		Status:      statusFilter,
+		Organizer:   organizerFilter,
+		Attendee:    attendeeFilter,
+		HasMeet:     hasMeetFilter.value,
+		AllDay:      allDayFilter,
+		Timed:       timedFilter,
+		MinDuration: minDuration,
+		Visibility:  visibilityFilter,
+		Query:       queryFilter,
+	}
+	if len(filter.Status) == 0 && !showDeclined {
+		filter.Status = []string{"accepted", "tentative", "needsAction"}
+	}
+	if err := filter.Validate(); err != nil {
+		log.Fatalf("Invalid filter: %v", err)
+	}
+	return filter
+}
+
+func getExporter() render.Exporter {
+	return cmdutil.NewExporter(format, exportOptions)
+}
+
+// parseDateRange returns the query bounds from --since, --until and --range
+func parseDateRange() (string, string, error) {
+	s, u := since, until
+	if rangeName != "" {
+		if until != "" {
+			return "", "", fmt.Errorf("--range cannot be used with --until")
+		}
+		var err error
+		s, u, err = parser.ExpandRange(rangeName, since)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return parser.ParseSinceUntil(s, u)
+}
+
+// setup applies the global settings from the flags, environment variables and config file
+func setup() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Warning: unable to load config: %v", err)
+		cfg = &config.Config{}
+	}
+	setupProfile(cfg)
+	setupTimeZone(cfg)
+}
+
+// setupProfile selects the profile from --profile, GALI_PROFILE or the profile config setting
+func setupProfile(cfg *config.Config) {
+	name := profile
+	if name == "" {
+		name = os.Getenv("GALI_PROFILE")
+	}
+	if name == "" {
+		name = cfg.Profile
+	}
+	if name == "" {
+		name = config.DefaultProfile
+	}
+	if err := config.ValidateProfileName(name); err != nil {
+		log.Fatalf("%v", err)
+	}
+	p := cfg.GetProfile(name)
+	gcalendar.SetProfile(name, p.Credentials)
+	if err := gcalendar.SetTokenStore(cfg.TokenStore); err != nil {
+		log.Fatalf("%v", err)
+	}
+	gcalendar.SetImpersonate(impersonate)
+	defaultCalendar = p.Calendar
+}
+
+// setupTimeZone applies --tz or the timezone config setting
+func setupTimeZone(cfg *config.Config) {
+	tz := timeZone
+	if tz == "" {
+		tz = cfg.TimeZone
+	}
+	if tz == config.TimeZoneCalendar {
+		useCalendarTimeZone = true
+		return
+	}
+	if err := parser.SetTimeZone(tz); err != nil {
+		log.Fatalf("Invalid timezone: %v", err)
+	}
+}
+
+// calendarOrDefault returns id, or the default calendar of the profile (primary if not configured) when id is empty
+func calendarOrDefault(id string) string {
+	if id != "" {
+		return id
+	}
+	if defaultCalendar != "" {
+		return defaultCalendar
+	}
+	return "primary"
+}
+
+// applyCalendarTimeZone switches to the calendar's own timezone when --tz calendar is specified
+func applyCalendarTimeZone(srv *calendar.Service, calendarID string) {
+	if !useCalendarTimeZone {
+		return
+	}
+	tz, err := gcalendar.GetCalendarTimeZone(srv, calendarID)
+	if err != nil {
+		log.Printf("Warning: unable to get timezone of %s: %v", calendarID, err)
+		return
+	}
+	if err := parser.SetTimeZone(tz); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// TimeZoneCalendar is a special timezone value that uses the calendar's own timezone
+const TimeZoneCalendar = "calendar"
+
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
+type Config struct {
+	// TimeZone is the IANA timezone used for query bounds and displayed times, or "calendar"
+	TimeZone string `json:"timezone,omitempty"`
+	// TokenStore is where OAuth tokens are stored (file, keyring or encrypted-file)
+	TokenStore string `json:"token_store,omitempty"`
+	// Profile is the current profile selected by gali auth switch
+	Profile string `json:"profile,omitempty"`
+	// Profiles are the account settings by profile name
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+}
+
+// Profile is the settings of an account
+type Profile struct {
+	// Credentials is the path of the OAuth client credentials JSON
+	Credentials string `json:"credentials,omitempty"`
+	// Calendar is the default calendar ID
+	Calendar string `json:"calendar,omitempty"`
+}
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidateProfileName checks that name can be used in a token file name
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name: %s (use letters, digits, '.', '_' or '-')", name)
+	}
+	return nil
+}
+
+// GetProfile returns the settings of the profile (empty settings if not configured)
+func (c *Config) GetProfile(name string) *Profile {
+	if p, ok := c.Profiles[name]; ok && p != nil {
+		return p
+	}
+	return &Profile{}
+}
+
+// SetProfile stores the settings of the profile
+func (c *Config) SetProfile(name string, p *Profile) {
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+	}
+	c.Profiles[name] = p
+}
+
+// ProfileNames returns the configured profile names and the default profile
+func (c *Config) ProfileNames() []string {
+	names := slices.Collect(maps.Keys(c.Profiles))
+	if !slices.Contains(names, DefaultProfile) {
+		names = append(names, DefaultProfile)
+	}
+	slices.Sort(names)
+	return names
+}
+
+type setting struct {
+	get      func(c *Config) string
+	set      func(c *Config, value string)
+	validate func(value string) error
+}
+
+var settings = map[string]setting{
+	"timezone": {
+		get: func(c *Config) string { return c.TimeZone },
+		set: func(c *Config, value string) { c.TimeZone = value },
+		validate: func(value string) error {
+			return ValidateTimeZone(value)
+		},
+	},
+	"token_store": {
+		get: func(c *Config) string { return c.TokenStore },
+		set: func(c *Config, value string) { c.TokenStore = value },
+		validate: func(value string) error {
+			if value != "" && !slices.Contains(TokenStoreValues, value) {
+				return fmt.Errorf("invalid token store: %s (must be one of %v)", value, TokenStoreValues)
+			}
+			return nil
+		},
+	},
+}
+
+// Token stores selectable by the token_store setting (see gcalendar.SetTokenStore)
+const (
+	TokenStoreFile          = "file"
+	TokenStoreKeyring       = "keyring"
+	TokenStoreEncryptedFile = "encrypted-file"
+)
+
+// TokenStoreValues are the token_store settings
+var TokenStoreValues = []string{TokenStoreFile, TokenStoreKeyring, TokenStoreEncryptedFile}
+
+// Keys returns the names of all settings
+func Keys() []string {
+	return slices.Sorted(maps.Keys(settings))
+}
+
+// ValidateTimeZone checks that tz is empty, "calendar" or a loadable IANA timezone
+func ValidateTimeZone(tz string) error {
+	if tz == "" || tz == TimeZoneCalendar {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("invalid timezone: %s", tz)
+	}
+	return nil
+}
+
+// Path returns the config file path from GALI_CONFIG or ~/.config/gali/config.json
+func Path() (string, error) {
+	if p := os.Getenv("GALI_CONFIG"); p != "" {
+		return p, nil
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("unable to get current user: %w", err)
+	}
+	return filepath.Join(usr.HomeDir, ".config", "gali", "config.json"), nil
+}
+
+// Load reads the config file; a missing file results in an empty config
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("unable to open config file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close config file: %v", closeErr)
+		}
+	}()
+	if err := json.NewDecoder(f).Decode(c); err != nil {
+		return nil, fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the config file
+func (c *Config) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("unable to create config directory: %w", err)
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0600); err != nil {
+		return fmt.Errorf("unable to write config file: %w", err)
+	}
+	return nil
+}
+
+// Get returns the value of a setting
+func (c *Config) Get(key string) (string, error) {
+	s, ok := settings[key]
+	if !ok {
+		return "", fmt.Errorf("unknown config key: %s (available: %v)", key, Keys())
+	}
+	return s.get(c), nil
+}
+
+// Set validates and updates the value of a setting
+func (c *Config) Set(key, value string) error {
+	s, ok := settings[key]
+	if !ok {
+		return fmt.Errorf("unknown config key: %s (available: %v)", key, Keys())
+	}
+	if s.validate != nil {
+		if err := s.validate(value); err != nil {
+			return err
+		}
+	}
+	s.set(c, value)
+	return nil
+}
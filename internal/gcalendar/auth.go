@@ -0,0 +1,252 @@
+package gcalendar
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+const (
+	AuthSourceOAuth          = "oauth"
+	AuthSourceADC            = "adc"
+	AuthSourceServiceAccount = "service_account"
+
+	serviceAccountKeyType = "service_account"
+
+	tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+	revokeURL    = "https://oauth2.googleapis.com/revoke"
+)
+
+// ErrNotLoggedIn is returned when the profile has no saved OAuth token
+var ErrNotLoggedIn = errors.New("not logged in (run gali auth login)")
+
+// AuthStatus describes the credentials used by the current profile
+type AuthStatus struct {
+	Profile     string    `json:"profile"`
+	Source      string    `json:"source"`
+	Credentials string    `json:"credentials,omitempty"`
+	Impersonate string    `json:"impersonate,omitempty"`
+	TokenFile   string    `json:"tokenFile,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Scopes      []string  `json:"scopes"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// getCopyPasteToken runs the authorization flow without a local server.
+// The user opens the link on any machine and pastes back the URL that the browser is redirected to.
+func getCopyPasteToken(config *oauth2.Config) (*oauth2.Token, error) {
+	config.RedirectURL = "http://localhost"
+	authURL, state, verifier, err := authCodeURL(config)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Go to the following link in a browser:\n%v\n", authURL)                                         // nolint
+	fmt.Fprint(os.Stderr, "After approving, the browser fails to load a localhost page. Paste the URL of that page here: ") // nolint
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("unable to read redirect URL: %w", err)
+	}
+	u, err := url.Parse(strings.TrimSpace(line))
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL: %w", err)
+	}
+	return exchangeCode(config, u.Query(), state, verifier)
+}
+
+// Login runs the OAuth authorization flow for the current profile and saves the token.
+// noBrowser uses the copy-paste flow for sessions without a local browser (e.g. SSH).
+func Login(noBrowser bool, scope ...string) error {
+	useScope := append(GetGaliScope(), scope...)
+	config, err := getGoogleConfig(useScope)
+	if err != nil {
+		return fmt.Errorf("unable to get Google API config: %w", err)
+	}
+	if config == nil {
+		return fmt.Errorf("OAuth client credentials not found (set GALI_OAUTH_CREDENTIALS_JSON, the profile credentials or place credentials.json; Application Default Credentials are managed by gcloud auth application-default login)")
+	}
+	var tok *oauth2.Token
+	if noBrowser {
+		tok, err = getCopyPasteToken(config)
+	} else {
+		tok, err = getTokenFromWeb(config)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Saving credential to: %s\n", GetTokenLocation(profileName)) // nolint
+	return storeToken(profileName, newSavedToken(tok, useScope))
+}
+
+// Logout revokes the saved OAuth token of the current profile and deletes the token file
+func Logout() error {
+	saved, err := loadToken(profileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotLoggedIn
+		}
+		return fmt.Errorf("unable to read token: %w", err)
+	}
+	token := saved.RefreshToken
+	if token == "" {
+		token = saved.AccessToken
+	}
+	if err := revokeToken(token); err != nil {
+		log.Printf("Warning: unable to revoke token: %v", err)
+	}
+	if err := deleteToken(profileName); err != nil {
+		return fmt.Errorf("unable to delete token: %w", err)
+	}
+	return nil
+}
+
+func revokeToken(token string) error {
+	resp, err := http.PostForm(revokeURL, url.Values{"token": {token}})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("revoke endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// GetToken returns a valid access token of the current profile (refreshed if needed) and its source
+func GetToken(scope ...string) (*oauth2.Token, string, error) {
+	useScope := append(GetGaliScope(), scope...)
+	ctx := context.Background()
+	config, err := getGoogleConfig(useScope)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to get Google API config: %w", err)
+	}
+	if config == nil {
+		creds, source, err := getDefaultCredentials(ctx, useScope)
+		if err != nil {
+			return nil, source, err
+		}
+		tok, err := creds.TokenSource.Token()
+		return tok, source, err
+	}
+	saved, err := loadToken(profileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, AuthSourceOAuth, ErrNotLoggedIn
+		}
+		return nil, AuthSourceOAuth, fmt.Errorf("unable to read token: %w", err)
+	}
+	tok, err := newSavingTokenSource(config, profileName, saved).Token()
+	return tok, AuthSourceOAuth, err
+}
+
+type tokenInfo struct {
+	Scope     string `json:"scope"`
+	ExpiresIn string `json:"expires_in"`
+	Email     string `json:"email"`
+}
+
+func getTokenInfo(accessToken string) (*tokenInfo, error) {
+	resp, err := http.Get(tokenInfoURL + "?access_token=" + url.QueryEscape(accessToken))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tokeninfo endpoint returned %s", resp.Status)
+	}
+	info := &tokenInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetAuthStatus returns the account, granted scopes and token expiry of the current profile
+func GetAuthStatus() (*AuthStatus, error) {
+	status := &AuthStatus{Profile: GetProfileName(), Impersonate: impersonate}
+	tok, source, err := GetToken()
+	status.Source = source
+	switch source {
+	case AuthSourceOAuth:
+		status.Credentials = getCredentialsFile()
+		status.TokenFile = GetTokenLocation(profileName)
+	case AuthSourceServiceAccount:
+		status.Credentials = getCredentialsFile()
+	}
+	if err != nil {
+		return status, err
+	}
+	status.Expiry = tok.Expiry
+
+	if info, err := getTokenInfo(tok.AccessToken); err != nil {
+		log.Printf("Warning: unable to get token info: %v", err)
+	} else {
+		status.Scopes = strings.Fields(info.Scope)
+		status.Email = info.Email
+		if status.Expiry.IsZero() {
+			if sec, err := strconv.Atoi(info.ExpiresIn); err == nil {
+				status.Expiry = time.Now().Add(time.Duration(sec) * time.Second)
+			}
+		}
+	}
+	if status.Email == "" {
+		// The ID of the primary calendar is the account email
+		srv, err := calendar.NewService(context.Background(), option.WithTokenSource(oauth2.StaticTokenSource(tok)))
+		if err == nil {
+			if primary, err := srv.CalendarList.Get("primary").Do(); err == nil {
+				status.Email = primary.Id
+			}
+		}
+	}
+	return status, nil
+}
+
+// RefreshToken refreshes the saved OAuth token of the current profile and saves it
+func RefreshToken() (*oauth2.Token, error) {
+	config, err := getGoogleConfig(GetGaliScope())
+	if err != nil {
+		return nil, fmt.Errorf("unable to get Google API config: %w", err)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("only OAuth tokens can be refreshed (service account tokens are issued on demand and Application Default Credentials are refreshed by gcloud)")
+	}
+	saved, err := loadToken(profileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotLoggedIn
+		}
+		return nil, fmt.Errorf("unable to read token: %w", err)
+	}
+	expired := saved.Token
+	expired.Expiry = time.Now().Add(-time.Minute)
+	tok, err := config.TokenSource(context.Background(), &expired).Token()
+	if err != nil {
+		return nil, fmt.Errorf("unable to refresh token: %w", err)
+	}
+	saved.Token = *tok
+	if err := storeToken(profileName, saved); err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
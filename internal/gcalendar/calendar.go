@@ -25,25 +25,15 @@ func ListCalendarListId(srv *calendar.Service) ([]string, error) {
 	return ids, nil
 }
 
-func GetIdMappedEvents(srv *calendar.Service, since, until string, calendarIDs ...string) []map[string]*calendar.Event {
-	getEvents := func(calID string) map[string]*calendar.Event {
-		events, err := ListEvents(srv, calID, since, until)
-		if err != nil {
-			log.Fatalf("Unable to retrieve events for %s: %v", calID, err)
-		}
-		m := make(map[string]*calendar.Event)
-		for _, item := range events.Items {
-			if GetSelfResponseStatus(item) == "declined" {
-				continue
-			}
-			m[item.Id] = item
-		}
-		return m
+// GetCalendarTimeZone returns the timezone of the calendar
+func GetCalendarTimeZone(srv *calendar.Service, calendarID string) (string, error) {
+	entry, err := srv.CalendarList.Get(calendarID).Do()
+	if err == nil && entry.TimeZone != "" {
+		return entry.TimeZone, nil
 	}
-
-	calendars := make([]map[string]*calendar.Event, len(calendarIDs))
-	for i, calID := range calendarIDs {
-		calendars[i] = getEvents(calID)
+	cal, err := srv.Calendars.Get(calendarID).Do()
+	if err != nil {
+		return "", err
 	}
-	return calendars
+	return cal.TimeZone, nil
 }
@@ -3,15 +3,23 @@ package gcalendar
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 
+	"github.com/srz-zumix/gali/internal/config"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	admdir "google.golang.org/api/admin/directory/v1"
@@ -19,28 +27,193 @@ import (
 	"google.golang.org/api/option"
 )
 
-func getClient(config *oauth2.Config) (*http.Client, error) {
+// savedToken is the token cache file format
+// Scopes records the granted scopes so that missing scopes can be requested incrementally.
+type savedToken struct {
+	oauth2.Token
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// grantedScopes returns the scopes of the token (tokens saved before scopes were recorded have the default scopes)
+func (t *savedToken) grantedScopes() []string {
+	if len(t.Scopes) == 0 {
+		return GetGaliScope()
+	}
+	return t.Scopes
+}
+
+// newSavedToken records the scopes granted to the token (the scope field of the token response, or the requested scopes)
+func newSavedToken(tok *oauth2.Token, requested []string) *savedToken {
+	scopes := requested
+	if s, ok := tok.Extra("scope").(string); ok && s != "" {
+		scopes = strings.Fields(s)
+	}
+	return &savedToken{Token: *tok, Scopes: scopes}
+}
+
+// missingScopes returns the scopes in want that are not in have
+func missingScopes(have, want []string) []string {
+	missing := []string{}
+	for _, s := range want {
+		if !slices.Contains(have, s) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// profileName and profileCredentials are set by SetProfile
+var (
+	profileName        string
+	profileCredentials string
+)
+
+// impersonate is the user that a service account acts as with domain-wide delegation
+var impersonate string
+
+// SetImpersonate sets the subject of the service account JWT (empty to use the service account itself)
+func SetImpersonate(subject string) {
+	impersonate = subject
+}
+
+// SetProfile selects the token cache and the OAuth client credentials (empty for the default) of a profile
+func SetProfile(name, credentials string) {
+	profileName = name
+	profileCredentials = credentials
+}
+
+// GetProfileName returns the name of the selected profile
+func GetProfileName() string {
+	if profileName == "" {
+		return config.DefaultProfile
+	}
+	return profileName
+}
+
+// GetTokenCacheFile returns the token cache file of the profile.
+// The default profile uses ~/.credentials/gali_token.json and others gali_token_<name>.json.
+func GetTokenCacheFile(name string) (string, error) {
+	dir, err := tokenCacheDir()
+	if err != nil {
+		return "", err
+	}
+	file := "gali_token.json"
+	if name != "" && name != config.DefaultProfile {
+		file = "gali_token_" + name + ".json"
+	}
+	return filepath.Join(dir, file), nil
+}
+
+// tokenCacheDir returns the directory of token files (replaced in tests)
+var tokenCacheDir = func() (string, error) {
 	usr, err := user.Current()
 	if err != nil {
-		return nil, fmt.Errorf("unable to get current user: %w", err)
+		return "", fmt.Errorf("unable to get current user: %w", err)
+	}
+	return filepath.Join(usr.HomeDir, ".credentials"), nil
+}
+
+// savingTokenSource saves the token to the cache file whenever it is refreshed
+type savingTokenSource struct {
+	base    oauth2.TokenSource
+	profile string
+	mu      sync.Mutex
+	saved   *savedToken
+}
+
+// newSavingTokenSource returns a token source that refreshes the saved token and writes refreshed tokens back
+func newSavingTokenSource(config *oauth2.Config, profile string, saved *savedToken) oauth2.TokenSource {
+	return &savingTokenSource{
+		base:    config.TokenSource(context.Background(), &saved.Token),
+		profile: profile,
+		saved:   saved,
 	}
-	tokenCacheDir := filepath.Join(usr.HomeDir, ".credentials")
-	tokenCacheFile := filepath.Join(tokenCacheDir, "gali_token.json")
+}
 
-	tok, err := tokenFromFile(tokenCacheFile)
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.base.Token()
 	if err != nil {
-		if err := os.MkdirAll(tokenCacheDir, 0700); err != nil {
-			return nil, fmt.Errorf("unable to create token cache directory: %w", err)
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tok.AccessToken != s.saved.AccessToken {
+		s.saved = &savedToken{Token: *tok, Scopes: s.saved.Scopes}
+		if err := storeToken(s.profile, s.saved); err != nil {
+			log.Printf("Warning: unable to save refreshed token: %v", err)
+		}
+	}
+	return tok, nil
+}
+
+func getClient(config *oauth2.Config) (*http.Client, error) {
+	saved, err := loadToken(profileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		// Do not overwrite a token that exists but cannot be read (e.g. wrong passphrase or locked keyring)
+		return nil, fmt.Errorf("unable to read token: %w", err)
+	}
+	if err == nil {
+		if missing := missingScopes(saved.grantedScopes(), config.Scopes); len(missing) > 0 {
+			// Request the additional scopes together with the already granted ones
+			fmt.Fprintf(os.Stderr, "Additional permission is required: %v\n", missing) // nolint
+			config.Scopes = append(saved.grantedScopes(), missing...)
+			err = fmt.Errorf("missing scopes: %v", missing)
 		}
-		tok, err = getTokenFromWeb(config)
+	}
+	if err != nil {
+		tok, err := getTokenFromWeb(config)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get token from web: %w", err)
 		}
-		if err := saveToken(tokenCacheFile, tok); err != nil {
+		saved = newSavedToken(tok, config.Scopes)
+		fmt.Fprintf(os.Stderr, "Saving credential to: %s\n", GetTokenLocation(profileName)) // nolint
+		if err := storeToken(profileName, saved); err != nil {
 			return nil, fmt.Errorf("unable to save token: %w", err)
 		}
 	}
-	return config.Client(context.Background(), tok), nil
+	return oauth2.NewClient(context.Background(), newSavingTokenSource(config, profileName, saved)), nil
+}
+
+// newOAuthState returns a random state parameter to protect the redirect against CSRF
+func newOAuthState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate OAuth state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// authCodeURL returns the authorization URL with a random state and a PKCE challenge, and the state and verifier
+func authCodeURL(config *oauth2.Config) (string, string, string, error) {
+	state, err := newOAuthState()
+	if err != nil {
+		return "", "", "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+	url := config.AuthCodeURL(state,
+		oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("include_granted_scopes", "true"),
+		oauth2.S256ChallengeOption(verifier))
+	return url, state, verifier, nil
+}
+
+// exchangeCode verifies the state of the redirect and exchanges the authorization code for a token
+func exchangeCode(config *oauth2.Config, query url.Values, state, verifier string) (*oauth2.Token, error) {
+	if e := query.Get("error"); e != "" {
+		return nil, fmt.Errorf("authorization failed: %s", e)
+	}
+	if query.Get("state") != state {
+		return nil, fmt.Errorf("OAuth state mismatch (the redirect did not come from this login request)")
+	}
+	code := query.Get("code")
+	if code == "" {
+		return nil, fmt.Errorf("no code received from browser redirect")
+	}
+	tok, err := config.Exchange(context.TODO(), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
+	}
+	return tok, nil
 }
 
 func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
@@ -56,53 +229,56 @@ func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
 	redirectURL := fmt.Sprintf("http://%s", ln.Addr().String())
 	config.RedirectURL = redirectURL
 
-	url := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser:\n%v\n", url)
+	authURL, state, verifier, err := authCodeURL(config)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Go to the following link in your browser:\n%v\n", authURL) // nolint
 
-	codeCh := make(chan string)
+	queryCh := make(chan url.Values)
 	errCh := make(chan error)
 	go func() {
-		conn, err := ln.Accept()
-		if err != nil {
-			errCh <- fmt.Errorf("unable to accept connection: %w", err)
-			return
-		}
-		defer func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				errCh <- fmt.Errorf("unable to accept connection: %w", err)
+				return
+			}
+			req, err := http.ReadRequest(bufio.NewReader(conn))
+			if err != nil {
+				log.Printf("Warning: unable to read request: %v", err)
+				_ = conn.Close()
+				continue
+			}
+			q := req.URL.Query()
+			if q.Get("state") == "" && q.Get("error") == "" {
+				// Not the OAuth redirect (e.g. favicon.ico)
+				_, _ = fmt.Fprintf(conn, "HTTP/1.1 404 Not Found\r\nContent-Length: 0\r\n\r\n")
+				_ = conn.Close()
+				continue
+			}
+			_, err = fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\nAuthentication complete. You may close this window.")
 			if closeErr := conn.Close(); closeErr != nil {
 				log.Printf("Warning: failed to close connection: %v", closeErr)
 			}
-		}()
-		req, err := http.ReadRequest(bufio.NewReader(conn))
-		if err != nil {
-			errCh <- fmt.Errorf("unable to read request: %w", err)
-			return
-		}
-		q := req.URL.Query()
-		code := q.Get("code")
-		_, err = fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\nAuthentication complete. You may close this window.")
-		if err != nil {
-			errCh <- fmt.Errorf("unable to write response: %w", err)
+			if err != nil {
+				errCh <- fmt.Errorf("unable to write response: %w", err)
+				return
+			}
+			queryCh <- q
 			return
 		}
-		codeCh <- code
 	}()
 
 	select {
-	case code := <-codeCh:
-		if code == "" {
-			return nil, fmt.Errorf("no code received from browser redirect")
-		}
-		tok, err := config.Exchange(context.TODO(), code)
-		if err != nil {
-			return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
-		}
-		return tok, nil
+	case q := <-queryCh:
+		return exchangeCode(config, q, state, verifier)
 	case err := <-errCh:
 		return nil, err
 	}
 }
 
-func tokenFromFile(file string) (*oauth2.Token, error) {
+func tokenFromFile(file string) (*savedToken, error) {
 	f, err := os.Open(file)
 	if err != nil {
 		return nil, err
@@ -112,17 +288,24 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 			log.Printf("Warning: failed to close file: %v", closeErr)
 		}
 	}()
-	tok := &oauth2.Token{}
+	tok := &savedToken{}
 	err = json.NewDecoder(f).Decode(tok)
 	return tok, err
 }
 
-func saveToken(path string, token *oauth2.Token) error {
-	fmt.Printf("Saving credential file to: %s\n", path)
-	f, err := os.Create(path)
+// saveToken writes the token as JSON readable only by the user
+func saveToken(path string, token *savedToken) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("unable to create token cache directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return fmt.Errorf("unable to create token file: %w", err)
 	}
+	// Restrict token files created by older versions with the default permissions
+	if err := f.Chmod(0600); err != nil {
+		log.Printf("Warning: unable to change token file permissions: %v", err)
+	}
 	defer func() {
 		if closeErr := f.Close(); closeErr != nil {
 			log.Printf("Warning: failed to close token file: %v", closeErr)
@@ -134,17 +317,41 @@ func saveToken(path string, token *oauth2.Token) error {
 	return nil
 }
 
+// getCredentialsFile returns the OAuth client credentials path of the profile,
+// GALI_OAUTH_CREDENTIALS_JSON or credentials.json
+func getCredentialsFile() string {
+	if profileCredentials != "" {
+		return profileCredentials
+	}
+	if f := os.Getenv("GALI_OAUTH_CREDENTIALS_JSON"); f != "" {
+		return f
+	}
+	return "credentials.json"
+}
+
+// isServiceAccountKey reports whether the credentials JSON is a service account key
+func isServiceAccountKey(b []byte) bool {
+	var f struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal(b, &f) == nil && f.Type == serviceAccountKeyType
+}
+
 // getGoogleConfig attempts to load Google API configuration,
 // first from credentials.json, and if not found, then from environment variables.
+// A nil config means that service account or Application Default Credentials are used (see getDefaultCredentials).
 func getGoogleConfig(scopes []string) (*oauth2.Config, error) {
-	// Try to read from credentials.json first
-	credentialsFile := os.Getenv("GALI_OAUTH_CREDENTIALS_JSON")
-	if credentialsFile == "" {
-		credentialsFile = "credentials.json"
-	}
+	// Try to read from the profile credentials or credentials.json first
+	credentialsFile := getCredentialsFile()
 
 	b, err := os.ReadFile(credentialsFile)
 	if err == nil {
+		if isServiceAccountKey(b) {
+			return nil, nil
+		}
+		if impersonate != "" {
+			return nil, fmt.Errorf("--impersonate requires service account credentials (%s is an OAuth client)", credentialsFile)
+		}
 		config, parseErr := google.ConfigFromJSON(b, scopes...)
 		if parseErr != nil {
 			return nil, fmt.Errorf("unable to parse credentials.json: %w", parseErr)
@@ -153,7 +360,7 @@ func getGoogleConfig(scopes []string) (*oauth2.Config, error) {
 	}
 
 	// If credentials.json not found, try environment variables
-	if os.IsNotExist(err) {
+	if os.IsNotExist(err) && profileCredentials == "" {
 		// Neither credentials.json nor environment variables are set.
 		return nil, nil // Signal to fallback to ADC
 	}
@@ -162,32 +369,82 @@ func getGoogleConfig(scopes []string) (*oauth2.Config, error) {
 	return nil, fmt.Errorf("unable to read %v: %w", credentialsFile, err)
 }
 
-func GetGaliScope() []string {
-	return []string{
-		calendar.CalendarReadonlyScope,
-		admdir.AdminDirectoryResourceCalendarReadonlyScope,
+// getDefaultCredentials returns the service account key credentials of the profile, or Application Default Credentials,
+// acting as the impersonated user when set, and the auth source
+func getDefaultCredentials(ctx context.Context, scopes []string) (*google.Credentials, string, error) {
+	params := google.CredentialsParams{Scopes: scopes, Subject: impersonate}
+	if b := readCredentialsFile(); isServiceAccountKey(b) {
+		creds, err := google.CredentialsFromJSONWithParams(ctx, b, params)
+		if err != nil {
+			return nil, AuthSourceServiceAccount, fmt.Errorf("unable to parse service account key %s: %w", getCredentialsFile(), err)
+		}
+		return creds, AuthSourceServiceAccount, nil
+	}
+	creds, err := google.FindDefaultCredentialsWithParams(ctx, params)
+	if err != nil {
+		return nil, AuthSourceADC, fmt.Errorf("unable to find Application Default Credentials: %w", err)
 	}
+	if impersonate != "" && !isServiceAccountKey(creds.JSON) {
+		// The subject is silently ignored for other credential types
+		return nil, AuthSourceADC, fmt.Errorf("--impersonate requires service account credentials (a service account key as the profile credentials or GOOGLE_APPLICATION_CREDENTIALS)")
+	}
+	return creds, AuthSourceADC, nil
 }
 
-func GetCalendarService() (*calendar.Service, error) {
-	useScope := GetGaliScope()
-	ctx := context.Background()
-	config, err := getGoogleConfig(useScope)
+// getClientOptions returns the options that authorize API requests with the credentials of the profile
+func getClientOptions(ctx context.Context, scopes []string) ([]option.ClientOption, error) {
+	config, err := getGoogleConfig(scopes)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get Google API config: %w", err)
 	}
-
-	var client *http.Client
-	options := []option.ClientOption{}
 	if config != nil {
-		client, err = getClient(config)
+		client, err := getClient(config)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get HTTP client: %w", err)
 		}
-		options = append(options, option.WithHTTPClient(client))
-	} else {
+		return []option.ClientOption{option.WithHTTPClient(client)}, nil
+	}
+	if impersonate == "" && !isServiceAccountKey(readCredentialsFile()) {
 		// Fallback to ADC
-		options = append(options, option.WithScopes(useScope...))
+		return []option.ClientOption{option.WithScopes(scopes...)}, nil
+	}
+	creds, _, err := getDefaultCredentials(ctx, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return []option.ClientOption{option.WithCredentials(creds)}, nil
+}
+
+// readCredentialsFile returns the content of the credentials file of the profile (nil if it cannot be read)
+func readCredentialsFile() []byte {
+	b, err := os.ReadFile(getCredentialsFile())
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// GetGaliWriteScope returns the scope required to create and modify events
+func GetGaliWriteScope() []string {
+	return []string{
+		calendar.CalendarEventsScope,
+	}
+}
+
+func GetGaliScope() []string {
+	return []string{
+		calendar.CalendarReadonlyScope,
+		admdir.AdminDirectoryResourceCalendarReadonlyScope,
+	}
+}
+
+// GetCalendarService returns the Calendar service; scope requests additional scopes (e.g. write access) on top of the default ones
+func GetCalendarService(scope ...string) (*calendar.Service, error) {
+	useScope := append(GetGaliScope(), scope...)
+	ctx := context.Background()
+	options, err := getClientOptions(ctx, useScope)
+	if err != nil {
+		return nil, err
 	}
 	srv, err := calendar.NewService(ctx, options...)
 	if err != nil {
@@ -197,24 +454,11 @@ func GetCalendarService() (*calendar.Service, error) {
 }
 
 func GetAdminDirectoryService(scope ...string) (*admdir.Service, error) {
-	useScope := GetGaliScope()
+	useScope := append(GetGaliScope(), scope...)
 	ctx := context.Background()
-	config, err := getGoogleConfig(useScope)
+	options, err := getClientOptions(ctx, useScope)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get Google API config: %w", err)
-	}
-
-	var client *http.Client
-	options := []option.ClientOption{}
-	if config != nil {
-		client, err = getClient(config)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get HTTP client: %w", err)
-		}
-		options = append(options, option.WithHTTPClient(client))
-	} else {
-		// Fallback to ADC
-		options = append(options, option.WithScopes(useScope...))
+		return nil, err
 	}
 	srv, err := admdir.NewService(ctx, options...)
 	if err != nil {
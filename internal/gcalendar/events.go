@@ -1,39 +1,153 @@
 package gcalendar
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
 	"google.golang.org/api/calendar/v3"
 )
 
+const listEventsPageSize = 250
+
+// MaxEvents is the maximum number of events ListEvents returns per calendar (0 means unlimited)
+var MaxEvents int64 = 2500
+
 // ListEvents lists events from the specified calendarID between since and until (inclusive)
+// It follows NextPageToken and merges all pages into a single result, up to MaxEvents items.
 func ListEvents(srv *calendar.Service, calendarID, since, until string) (*calendar.Events, error) {
-	call := srv.Events.List(calendarID).ShowDeleted(false).SingleEvents(true).OrderBy("startTime").MaxResults(100)
-	if since != "" {
-		call = call.TimeMin(since)
+	return ListEventsQuery(srv, calendarID, since, until, "")
+}
+
+// ListEventsQuery is ListEvents with a free text query (the API q parameter, empty for all events)
+func ListEventsQuery(srv *calendar.Service, calendarID, since, until, q string) (*calendar.Events, error) {
+	var all *calendar.Events
+	var count int64
+	pageToken := ""
+	for {
+		pageSize := int64(listEventsPageSize)
+		if MaxEvents > 0 {
+			pageSize = min(pageSize, MaxEvents-count)
+		}
+		call := srv.Events.List(calendarID).ShowDeleted(false).SingleEvents(true).OrderBy("startTime").MaxResults(pageSize)
+		if since != "" {
+			call = call.TimeMin(since)
+		}
+		if until != "" {
+			call = call.TimeMax(until)
+		}
+		if q != "" {
+			call = call.Q(q)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		if all == nil {
+			all = resp
+		} else {
+			all.Items = append(all.Items, resp.Items...)
+		}
+		count += int64(len(resp.Items))
+		if resp.NextPageToken == "" {
+			break
+		}
+		if MaxEvents > 0 && count >= MaxEvents {
+			log.Printf("Warning: %s has more than %d events in the range, remaining events are omitted (raise --max-events)", calendarID, MaxEvents)
+			break
+		}
+		pageToken = resp.NextPageToken
 	}
-	if until != "" {
-		call = call.TimeMax(until)
+	all.NextPageToken = ""
+	return all, nil
+}
+
+// GetListedCalendarId returns the ID of the calendar that events were listed from.
+// The primary calendar is shown by its real ID (the account email), which is its summary.
+func GetListedCalendarId(calendarID string, events *calendar.Events) string {
+	if calendarID == "primary" && events != nil && events.Summary != "" {
+		return events.Summary
 	}
-	return call.Do()
+	return calendarID
+}
+
+// GetEventCalendars maps event IDs to the calendar each event was listed from (the first one for events in several calendars).
+// results are the events of calendarIDs in the same order, as returned by ListEventsMulti.
+func GetEventCalendars(calendarIDs []string, results []*calendar.Events) map[string]string {
+	calendars := map[string]string{}
+	for i, events := range results {
+		if events == nil {
+			continue
+		}
+		id := GetListedCalendarId(calendarIDs[i], events)
+		for _, item := range events.Items {
+			if _, ok := calendars[item.Id]; !ok {
+				calendars[item.Id] = id
+			}
+		}
+	}
+	return calendars
+}
+
+// Concurrency is the number of calendars fetched in parallel by ListEventsMulti
+var Concurrency = 4
+
+// ListEventsMulti lists events of each calendarID, matching q when not empty, using a bounded worker pool.
+// Results are returned in the same order as calendarIDs (see GetEventCalendars); a calendar that fails has a nil entry
+// and its error is included in the returned (joined) error.
+func ListEventsMulti(srv *calendar.Service, calendarIDs []string, since, until, q string) ([]*calendar.Events, error) {
+	results := make([]*calendar.Events, len(calendarIDs))
+	errs := make([]error, len(calendarIDs))
+
+	workers := max(1, min(Concurrency, len(calendarIDs)))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				events, err := ListEventsQuery(srv, calendarIDs[i], since, until, q)
+				if err != nil {
+					errs[i] = fmt.Errorf("%s: %w", calendarIDs[i], err)
+					continue
+				}
+				results[i] = events
+			}
+		}()
+	}
+	for i := range calendarIDs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, errors.Join(errs...)
 }
 
 // GetUnionMappedEvents gets a map of event ID to event from reference calendar IDs
 func GetUnionMappedEvents(srv *calendar.Service, calendarIDs []string, since, until string) (map[string]*calendar.Event, error) {
 	unionEvents := map[string]*calendar.Event{}
-	for _, id := range calendarIDs {
-		refEvents, err := ListEvents(srv, id, since, until)
-		if err == nil {
-			for _, item := range refEvents.Items {
-				if current, ok := unionEvents[item.Id]; !ok {
+	results, err := ListEventsMulti(srv, calendarIDs, since, until, "")
+	for _, refEvents := range results {
+		if refEvents == nil {
+			continue
+		}
+		for _, item := range refEvents.Items {
+			if current, ok := unionEvents[item.Id]; !ok {
+				unionEvents[item.Id] = item
+			} else {
+				if current.Summary == "" && item.Summary != "" {
 					unionEvents[item.Id] = item
-				} else {
-					if current.Summary == "" && item.Summary != "" {
-						unionEvents[item.Id] = item
-					}
 				}
 			}
 		}
 	}
-	return unionEvents, nil
+	return unionEvents, err
 }
 
 // CompletePrivateEvents replaces private events in mainEvents with ref events if available
@@ -60,6 +174,31 @@ func CompletePrivateEvents(mainEvents *calendar.Events, refEventMap map[string]*
 	}
 }
 
+// SortEventsByStartTime sorts events by start date/time (all-day events use the start date)
+func SortEventsByStartTime(events *calendar.Events) {
+	start := func(e *calendar.Event) string {
+		if e.Start.DateTime != "" {
+			return e.Start.DateTime
+		}
+		return e.Start.Date
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return start(events.Items[i]) < start(events.Items[j])
+	})
+}
+
+// GetConferenceLink returns the video conference link of the event
+func GetConferenceLink(event *calendar.Event) string {
+	if event.ConferenceData != nil {
+		for _, entry := range event.ConferenceData.EntryPoints {
+			if entry.EntryPointType == "video" && entry.Uri != "" {
+				return entry.Uri
+			}
+		}
+	}
+	return event.HangoutLink
+}
+
 func GetSelfResponseStatus(event *calendar.Event) string {
 	if event.Attendees != nil {
 		for _, attendee := range event.Attendees {
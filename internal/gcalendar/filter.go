@@ -0,0 +1,141 @@
+package gcalendar
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+var ResponseStatusValues = []string{"accepted", "tentative", "needsAction", "declined"}
+
+var VisibilityValues = []string{"default", "public", "private", "confidential"}
+
+// ValidateVisibility checks a visibility value (empty means unspecified)
+func ValidateVisibility(v string) error {
+	if v != "" && !slices.Contains(VisibilityValues, v) {
+		return fmt.Errorf("invalid visibility: %s (must be one of %v)", v, VisibilityValues)
+	}
+	return nil
+}
+
+// EventFilter selects events by their attributes.
+// Query is passed to the API; the other conditions are applied to the fetched events.
+// Conditions are combined with AND, and multiple values of a condition with OR.
+type EventFilter struct {
+	// Status is the user's response status (an event without attendees counts as accepted)
+	Status []string
+	// Organizer matches the organizer email or name (partial, case insensitive)
+	Organizer []string
+	// Attendee matches an attendee email or name (partial, case insensitive)
+	Attendee []string
+	// HasMeet selects events with (true) or without (false) a conference link when not nil
+	HasMeet     *bool
+	AllDay      bool
+	Timed       bool
+	MinDuration time.Duration
+	Visibility  []string
+	Query       string
+}
+
+// Validate checks the values of the filter
+func (f *EventFilter) Validate() error {
+	for _, s := range f.Status {
+		if !slices.Contains(ResponseStatusValues, s) {
+			return fmt.Errorf("invalid status: %s (must be one of %v)", s, ResponseStatusValues)
+		}
+	}
+	for _, v := range f.Visibility {
+		if err := ValidateVisibility(v); err != nil {
+			return err
+		}
+	}
+	if f.AllDay && f.Timed {
+		return fmt.Errorf("--all-day and --timed cannot be used together")
+	}
+	return nil
+}
+
+// containsFold reports whether any of the values is a case insensitive substring of one of the texts
+func containsFold(values []string, texts ...string) bool {
+	for _, v := range values {
+		v = strings.ToLower(v)
+		for _, text := range texts {
+			if text != "" && strings.Contains(strings.ToLower(text), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Match reports whether the event satisfies the filter (a nil filter matches every event)
+func (f *EventFilter) Match(e *calendar.Event) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Status) > 0 {
+		status := GetSelfResponseStatus(e)
+		if status == "" {
+			status = "accepted"
+		}
+		if !slices.Contains(f.Status, status) {
+			return false
+		}
+	}
+	if len(f.Organizer) > 0 {
+		if e.Organizer == nil || !containsFold(f.Organizer, e.Organizer.Email, e.Organizer.DisplayName) {
+			return false
+		}
+	}
+	if len(f.Attendee) > 0 {
+		if !slices.ContainsFunc(e.Attendees, func(a *calendar.EventAttendee) bool {
+			return containsFold(f.Attendee, a.Email, a.DisplayName)
+		}) {
+			return false
+		}
+	}
+	if f.HasMeet != nil && (GetConferenceLink(e) != "") != *f.HasMeet {
+		return false
+	}
+	allDay := e.Start != nil && e.Start.DateTime == ""
+	if (f.AllDay && !allDay) || (f.Timed && allDay) {
+		return false
+	}
+	if f.MinDuration > 0 {
+		start, end, err := GetEventTimeRange(e)
+		if err != nil || end.Sub(start) < f.MinDuration {
+			return false
+		}
+	}
+	if len(f.Visibility) > 0 {
+		visibility := e.Visibility
+		if visibility == "" {
+			visibility = "default"
+		}
+		if !slices.Contains(f.Visibility, visibility) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply removes the events that do not match the filter
+func (f *EventFilter) Apply(events *calendar.Events) {
+	if f == nil || events == nil {
+		return
+	}
+	events.Items = slices.DeleteFunc(events.Items, func(e *calendar.Event) bool {
+		return !f.Match(e)
+	})
+}
+
+// query returns the free text query passed to the API
+func (f *EventFilter) query() string {
+	if f == nil {
+		return ""
+	}
+	return f.Query
+}
@@ -0,0 +1,168 @@
+package gcalendar
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// freeBusyMaxItems is the maximum number of calendars per freebusy query
+const freeBusyMaxItems = 50
+
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (s TimeSlot) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+type FreeSlotOptions struct {
+	// Duration is the minimum length of a free slot
+	Duration time.Duration
+	// DayStart and DayEnd are the working hours as offsets from midnight (DayEnd 0 means end of day)
+	DayStart time.Duration
+	DayEnd   time.Duration
+	// SkipWeekends excludes Saturdays and Sundays
+	SkipWeekends bool
+	// Location is the timezone of the working hours
+	Location *time.Location
+}
+
+// QueryFreeBusy queries busy periods of the calendars between since and until.
+// Requests are split into chunks so that any number of calendars can be queried.
+func QueryFreeBusy(srv *calendar.Service, calendarIDs []string, since, until string) (map[string]calendar.FreeBusyCalendar, error) {
+	calendars := map[string]calendar.FreeBusyCalendar{}
+	for chunk := range slices.Chunk(calendarIDs, freeBusyMaxItems) {
+		items := make([]*calendar.FreeBusyRequestItem, len(chunk))
+		for i, id := range chunk {
+			items[i] = &calendar.FreeBusyRequestItem{Id: id}
+		}
+		resp, err := srv.Freebusy.Query(&calendar.FreeBusyRequest{
+			TimeMin: since,
+			TimeMax: until,
+			Items:   items,
+		}).Do()
+		if err != nil {
+			return nil, err
+		}
+		for id, cal := range resp.Calendars {
+			calendars[id] = cal
+		}
+	}
+	return calendars, nil
+}
+
+// GetBusyPeriods returns the merged busy periods of all calendars sorted by start time.
+// It fails when the free/busy of a calendar is unavailable (e.g. notFound), because the calendar would look free.
+func GetBusyPeriods(calendars map[string]calendar.FreeBusyCalendar) ([]TimeSlot, error) {
+	unavailable := []string{}
+	for _, id := range slices.Sorted(maps.Keys(calendars)) {
+		for _, e := range calendars[id].Errors {
+			unavailable = append(unavailable, id+" ("+e.Reason+")")
+		}
+	}
+	if len(unavailable) > 0 {
+		return nil, fmt.Errorf("free/busy is unavailable for %s", strings.Join(unavailable, ", "))
+	}
+	busy := []TimeSlot{}
+	for _, cal := range calendars {
+		for _, p := range cal.Busy {
+			start, err := time.Parse(time.RFC3339, p.Start)
+			if err != nil {
+				continue
+			}
+			end, err := time.Parse(time.RFC3339, p.End)
+			if err != nil {
+				continue
+			}
+			busy = append(busy, TimeSlot{Start: start, End: end})
+		}
+	}
+	return mergeTimeSlots(busy), nil
+}
+
+func mergeTimeSlots(slots []TimeSlot) []TimeSlot {
+	slices.SortFunc(slots, func(a, b TimeSlot) int {
+		return a.Start.Compare(b.Start)
+	})
+	merged := []TimeSlot{}
+	for _, s := range slots {
+		if n := len(merged); n > 0 && !s.Start.After(merged[n-1].End) {
+			if s.End.After(merged[n-1].End) {
+				merged[n-1].End = s.End
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// FindFreeSlots returns the free slots between start and end that do not overlap busy periods
+func FindFreeSlots(busy []TimeSlot, start, end time.Time, opts FreeSlotOptions) ([]TimeSlot, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	dayEnd := opts.DayEnd
+	if dayEnd == 0 {
+		dayEnd = 24 * time.Hour
+	}
+	if opts.DayStart >= dayEnd {
+		return nil, fmt.Errorf("working hours start must be before end")
+	}
+
+	busy = mergeTimeSlots(busy)
+	free := []TimeSlot{}
+	s := start.In(loc)
+	for day := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if opts.SkipWeekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		windowStart := clockOnDay(day, opts.DayStart)
+		windowEnd := clockOnDay(day, dayEnd)
+		if windowStart.Before(start) {
+			windowStart = start
+		}
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		cursor := windowStart
+		for _, b := range busy {
+			if !b.End.After(cursor) {
+				continue
+			}
+			if !b.Start.Before(windowEnd) {
+				break
+			}
+			if b.Start.After(cursor) {
+				free = appendSlot(free, TimeSlot{Start: cursor, End: b.Start}, opts.Duration)
+			}
+			cursor = b.End
+		}
+		if cursor.Before(windowEnd) {
+			free = appendSlot(free, TimeSlot{Start: cursor, End: windowEnd}, opts.Duration)
+		}
+	}
+	return free, nil
+}
+
+// clockOnDay returns the wall clock time of day on the given date (calendar arithmetic, DST safe)
+func clockOnDay(day time.Time, clock time.Duration) time.Time {
+	h := int(clock / time.Hour)
+	m := int((clock % time.Hour) / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, day.Location())
+}
+
+func appendSlot(slots []TimeSlot, slot TimeSlot, minDuration time.Duration) []TimeSlot {
+	if slot.Duration() <= 0 || slot.Duration() < minDuration {
+		return slots
+	}
+	return append(slots, slot)
+}
@@ -0,0 +1,171 @@
+package gcalendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/srz-zumix/gali/internal/parser"
+	"google.golang.org/api/calendar/v3"
+)
+
+type MatchBy string
+
+const (
+	MatchByID      MatchBy = "id"
+	MatchByICalUID MatchBy = "icaluid"
+	MatchByOverlap MatchBy = "overlap"
+)
+
+var MatchByValues = []string{
+	string(MatchByID),
+	string(MatchByICalUID),
+	string(MatchByOverlap),
+}
+
+// ParseMatchBy validates the --match-by value
+func ParseMatchBy(s string) (MatchBy, error) {
+	switch MatchBy(s) {
+	case "":
+		return MatchByID, nil
+	case MatchByID, MatchByICalUID, MatchByOverlap:
+		return MatchBy(s), nil
+	}
+	return "", fmt.Errorf("invalid match-by value: %s (must be one of %v)", s, MatchByValues)
+}
+
+// EventSet is a collection of events that can be tested for membership with a MatchBy rule
+type EventSet struct {
+	MatchBy MatchBy
+	// CalendarID is the calendar the events were listed from (see GetListedCalendarId)
+	CalendarID string
+	Items      []*calendar.Event
+	keys       map[string]struct{}
+}
+
+func NewEventSet(matchBy MatchBy) *EventSet {
+	return &EventSet{
+		MatchBy: matchBy,
+		Items:   []*calendar.Event{},
+		keys:    map[string]struct{}{},
+	}
+}
+
+// eventKey returns the identity key of an event, or "" when the rule is not key based
+func eventKey(e *calendar.Event, matchBy MatchBy) string {
+	switch matchBy {
+	case MatchByICalUID:
+		if e.ICalUID == "" {
+			return e.Id
+		}
+		// Instances of a recurring event share the iCalUID, so distinguish them by start time
+		start := e.OriginalStartTime
+		if start == nil {
+			start = e.Start
+		}
+		if start == nil {
+			return e.ICalUID
+		}
+		if start.DateTime != "" {
+			if t, err := time.Parse(time.RFC3339, start.DateTime); err == nil {
+				return e.ICalUID + "/" + t.UTC().Format(time.RFC3339)
+			}
+			return e.ICalUID + "/" + start.DateTime
+		}
+		return e.ICalUID + "/" + start.Date
+	case MatchByOverlap:
+		return ""
+	default:
+		return e.Id
+	}
+}
+
+// Add appends the event to the set
+func (s *EventSet) Add(e *calendar.Event) {
+	s.Items = append(s.Items, e)
+	if key := eventKey(e, s.MatchBy); key != "" {
+		s.keys[key] = struct{}{}
+	}
+}
+
+// Contains reports whether the set has an event matching e
+func (s *EventSet) Contains(e *calendar.Event) bool {
+	if s.MatchBy != MatchByOverlap {
+		_, ok := s.keys[eventKey(e, s.MatchBy)]
+		return ok
+	}
+	for _, item := range s.Items {
+		if EventsOverlap(e, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEventTimeRange returns the start and end time of an event
+// All-day events are interpreted in the configured timezone.
+func GetEventTimeRange(e *calendar.Event) (time.Time, time.Time, error) {
+	parse := func(dt *calendar.EventDateTime) (time.Time, error) {
+		if dt == nil {
+			return time.Time{}, fmt.Errorf("missing event time")
+		}
+		if dt.DateTime != "" {
+			return time.Parse(time.RFC3339, dt.DateTime)
+		}
+		return time.ParseInLocation("2006-01-02", dt.Date, parser.GetLocation())
+	}
+	start, err := parse(e.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := parse(e.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end, nil
+}
+
+// EventsOverlap reports whether the time ranges of two events overlap
+func EventsOverlap(a, b *calendar.Event) bool {
+	aStart, aEnd, err := GetEventTimeRange(a)
+	if err != nil {
+		return false
+	}
+	bStart, bEnd, err := GetEventTimeRange(b)
+	if err != nil {
+		return false
+	}
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// GetEventSets fetches events of each calendar and returns them as EventSets in the same order as calendarIDs.
+// Only events that match the filter are included.
+func GetEventSets(srv *calendar.Service, since, until string, matchBy MatchBy, filter *EventFilter, calendarIDs ...string) ([]*EventSet, error) {
+	results, err := ListEventsMulti(srv, calendarIDs, since, until, filter.query())
+	sets := make([]*EventSet, len(calendarIDs))
+	for i, events := range results {
+		set := NewEventSet(matchBy)
+		set.CalendarID = GetListedCalendarId(calendarIDs[i], events)
+		if events != nil {
+			for _, item := range events.Items {
+				if filter.Match(item) {
+					set.Add(item)
+				}
+			}
+		}
+		sets[i] = set
+	}
+	return sets, err
+}
+
+// GetEventSetCalendars maps event IDs to the calendar of the first set that has the event
+func GetEventSetCalendars(sets []*EventSet) map[string]string {
+	calendars := map[string]string{}
+	for _, set := range sets {
+		for _, item := range set.Items {
+			if _, ok := calendars[item.Id]; !ok {
+				calendars[item.Id] = set.CalendarID
+			}
+		}
+	}
+	return calendars
+}
@@ -0,0 +1,259 @@
+package gcalendar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+var SendUpdatesValues = []string{"all", "external", "none"}
+
+// ParseSendUpdates converts --send-updates (all, external or none) to the API value
+func ParseSendUpdates(s string) (string, error) {
+	switch s {
+	case "all", "none":
+		return s, nil
+	case "external", "externalOnly":
+		return "externalOnly", nil
+	}
+	return "", fmt.Errorf("invalid send-updates value: %s (must be one of %v)", s, SendUpdatesValues)
+}
+
+// NewConferenceCreateRequest returns conference data that requests a new Google Meet conference
+func NewConferenceCreateRequest() (*calendar.ConferenceData, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("unable to generate conference request ID: %w", err)
+	}
+	return &calendar.ConferenceData{
+		CreateRequest: &calendar.CreateConferenceRequest{
+			RequestId: hex.EncodeToString(b),
+			ConferenceSolutionKey: &calendar.ConferenceSolutionKey{
+				Type: "hangoutsMeet",
+			},
+		},
+	}, nil
+}
+
+// InsertEvent creates an event in the calendar
+func InsertEvent(srv *calendar.Service, calendarID string, event *calendar.Event, sendUpdates string) (*calendar.Event, error) {
+	call := srv.Events.Insert(calendarID, event).SendUpdates(sendUpdates)
+	if event.ConferenceData != nil {
+		call = call.ConferenceDataVersion(1)
+	}
+	return call.Do()
+}
+
+// QuickAddEvent creates an event from a text string using the server-side parser
+func QuickAddEvent(srv *calendar.Service, calendarID, text, sendUpdates string) (*calendar.Event, error) {
+	return srv.Events.QuickAdd(calendarID, text).SendUpdates(sendUpdates).Do()
+}
+
+// GetEvent returns the event (or an instance of a recurring event) by ID
+func GetEvent(srv *calendar.Service, calendarID, eventID string) (*calendar.Event, error) {
+	return srv.Events.Get(calendarID, eventID).Do()
+}
+
+// UpdateEvent replaces the event in the calendar
+func UpdateEvent(srv *calendar.Service, calendarID string, event *calendar.Event, sendUpdates string) (*calendar.Event, error) {
+	call := srv.Events.Update(calendarID, event.Id, event).SendUpdates(sendUpdates)
+	if event.ConferenceData != nil {
+		call = call.ConferenceDataVersion(1)
+	}
+	return call.Do()
+}
+
+// MoveEvent moves the event to the destination calendar
+func MoveEvent(srv *calendar.Service, calendarID, eventID, destination, sendUpdates string) (*calendar.Event, error) {
+	return srv.Events.Move(calendarID, eventID, destination).SendUpdates(sendUpdates).Do()
+}
+
+// DeleteEvent deletes the event from the calendar
+func DeleteEvent(srv *calendar.Service, calendarID, eventID, sendUpdates string) error {
+	return srv.Events.Delete(calendarID, eventID).SendUpdates(sendUpdates).Do()
+}
+
+type ChangeAction string
+
+const (
+	ChangeInsert ChangeAction = "insert"
+	ChangeUpdate ChangeAction = "update"
+	ChangeMove   ChangeAction = "move"
+	ChangeDelete ChangeAction = "delete"
+)
+
+// EventChange is a single API call planned by edit, move or delete
+type EventChange struct {
+	Action      ChangeAction    `json:"action"`
+	CalendarID  string          `json:"calendarId"`
+	Destination string          `json:"destination,omitempty"`
+	Before      *calendar.Event `json:"before,omitempty"`
+	After       *calendar.Event `json:"after,omitempty"`
+}
+
+// getSeries returns the recurring event of an instance, or nil when the event is not an instance
+func getSeries(srv *calendar.Service, calendarID string, event *calendar.Event, scope ModifyScope) (*calendar.Event, error) {
+	if event.RecurringEventId == "" || scope == ScopeInstance {
+		return nil, nil
+	}
+	series, err := GetEvent(srv, calendarID, event.RecurringEventId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get recurring event %s: %w", event.RecurringEventId, err)
+	}
+	return series, nil
+}
+
+// PlanUpdate returns the changes that apply edit to the event in the scope.
+// For the all scope the time shift made to the instance is applied to the whole series.
+// For the following scope the series is split into two at the instance.
+func PlanUpdate(srv *calendar.Service, calendarID string, event *calendar.Event, scope ModifyScope, edit func(*calendar.Event) error) ([]*EventChange, error) {
+	after, err := CloneEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(after); err != nil {
+		return nil, err
+	}
+	series, err := getSeries(srv, calendarID, event, scope)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		return []*EventChange{{Action: ChangeUpdate, CalendarID: calendarID, Before: event, After: after}}, nil
+	}
+
+	if scope == ScopeFollowing && !isFirstOccurrence(series, event) {
+		truncated, following, err := splitSeries(srv, calendarID, series, event)
+		if err != nil {
+			return nil, err
+		}
+		if err := edit(following); err != nil {
+			return nil, err
+		}
+		// Insert the continuation first so that a failed insert leaves the original series intact
+		return []*EventChange{
+			{Action: ChangeInsert, CalendarID: calendarID, After: following},
+			{Action: ChangeUpdate, CalendarID: calendarID, Before: series, After: truncated},
+		}, nil
+	}
+
+	target, err := CloneEvent(series)
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(target); err != nil {
+		return nil, err
+	}
+	target.Start, target.End = series.Start, series.End
+	oldStart, oldEnd, err := GetEventTimeRange(event)
+	if err != nil {
+		return nil, err
+	}
+	newStart, newEnd, err := GetEventTimeRange(after)
+	if err != nil {
+		return nil, err
+	}
+	if !newStart.Equal(oldStart) || !newEnd.Equal(oldEnd) {
+		loc := eventLocation(series.Start, oldStart)
+		delta := wallClock(newStart, loc).Sub(wallClock(oldStart, loc))
+		target.Start, target.End, err = ShiftEventTimes(series, delta, newEnd.Sub(newStart))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return []*EventChange{{Action: ChangeUpdate, CalendarID: calendarID, Before: series, After: target}}, nil
+}
+
+// PlanMove returns the changes that move the event in the scope to the destination calendar.
+// Single instances of a recurring event cannot be moved.
+func PlanMove(srv *calendar.Service, calendarID string, event *calendar.Event, scope ModifyScope, destination string) ([]*EventChange, error) {
+	if event.RecurringEventId != "" && scope == ScopeInstance {
+		return nil, fmt.Errorf("an instance of a recurring event cannot be moved to another calendar (use --scope following or all)")
+	}
+	series, err := getSeries(srv, calendarID, event, scope)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		return []*EventChange{{Action: ChangeMove, CalendarID: calendarID, Destination: destination, Before: event}}, nil
+	}
+	if scope == ScopeFollowing && !isFirstOccurrence(series, event) {
+		truncated, following, err := splitSeries(srv, calendarID, series, event)
+		if err != nil {
+			return nil, err
+		}
+		// Insert the continuation first so that a failed insert leaves the original series intact
+		return []*EventChange{
+			{Action: ChangeInsert, CalendarID: destination, After: following},
+			{Action: ChangeUpdate, CalendarID: calendarID, Before: series, After: truncated},
+		}, nil
+	}
+	return []*EventChange{{Action: ChangeMove, CalendarID: calendarID, Destination: destination, Before: series}}, nil
+}
+
+// PlanDelete returns the changes that delete the event in the scope
+func PlanDelete(srv *calendar.Service, calendarID string, event *calendar.Event, scope ModifyScope) ([]*EventChange, error) {
+	series, err := getSeries(srv, calendarID, event, scope)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		return []*EventChange{{Action: ChangeDelete, CalendarID: calendarID, Before: event}}, nil
+	}
+	if scope == ScopeFollowing && !isFirstOccurrence(series, event) {
+		truncated, err := CloneEvent(series)
+		if err != nil {
+			return nil, err
+		}
+		truncated.Recurrence, err = TruncateRecurrence(series.Recurrence, event.OriginalStartTime)
+		if err != nil {
+			return nil, err
+		}
+		return []*EventChange{{Action: ChangeUpdate, CalendarID: calendarID, Before: series, After: truncated}}, nil
+	}
+	return []*EventChange{{Action: ChangeDelete, CalendarID: calendarID, Before: series}}, nil
+}
+
+// ApplyEventChanges executes the changes in order and returns the inserted, updated and moved events.
+// When a change fails, the events inserted by the preceding changes are deleted again.
+func ApplyEventChanges(srv *calendar.Service, changes []*EventChange, sendUpdates string) ([]*calendar.Event, error) {
+	results := []*calendar.Event{}
+	inserted := []*EventChange{}
+	for _, c := range changes {
+		var event *calendar.Event
+		var err error
+		switch c.Action {
+		case ChangeInsert:
+			event, err = InsertEvent(srv, c.CalendarID, c.After, sendUpdates)
+		case ChangeUpdate:
+			event, err = UpdateEvent(srv, c.CalendarID, c.After, sendUpdates)
+		case ChangeMove:
+			event, err = MoveEvent(srv, c.CalendarID, c.Before.Id, c.Destination, sendUpdates)
+		case ChangeDelete:
+			err = DeleteEvent(srv, c.CalendarID, c.Before.Id, sendUpdates)
+		}
+		if err != nil {
+			rollbackInserts(srv, inserted)
+			return results, fmt.Errorf("unable to %s event: %w", c.Action, err)
+		}
+		if c.Action == ChangeInsert {
+			inserted = append(inserted, &EventChange{Action: ChangeDelete, CalendarID: c.CalendarID, Before: event})
+		}
+		if event != nil {
+			results = append(results, event)
+		}
+	}
+	return results, nil
+}
+
+// rollbackInserts deletes the inserted events (without notifying attendees)
+func rollbackInserts(srv *calendar.Service, inserted []*EventChange) {
+	for _, c := range inserted {
+		if err := DeleteEvent(srv, c.CalendarID, c.Before.Id, "none"); err != nil {
+			log.Printf("Warning: unable to delete inserted event %s: %v", c.Before.Id, err)
+		}
+	}
+}
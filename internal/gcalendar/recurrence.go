@@ -0,0 +1,254 @@
+package gcalendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/srz-zumix/gali/internal/parser"
+	"google.golang.org/api/calendar/v3"
+)
+
+// ModifyScope selects which occurrences of a recurring event are changed
+type ModifyScope string
+
+const (
+	ScopeInstance  ModifyScope = "instance"
+	ScopeFollowing ModifyScope = "following"
+	ScopeAll       ModifyScope = "all"
+)
+
+var ModifyScopeValues = []string{
+	string(ScopeInstance),
+	string(ScopeFollowing),
+	string(ScopeAll),
+}
+
+// ParseModifyScope validates the --scope value
+func ParseModifyScope(s string) (ModifyScope, error) {
+	switch ModifyScope(s) {
+	case "":
+		return ScopeInstance, nil
+	case ScopeInstance, ScopeFollowing, ScopeAll:
+		return ModifyScope(s), nil
+	}
+	return "", fmt.Errorf("invalid scope value: %s (must be one of %v)", s, ModifyScopeValues)
+}
+
+// CloneEvent returns a deep copy of the event
+func CloneEvent(event *calendar.Event) (*calendar.Event, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	clone := &calendar.Event{}
+	if err := json.Unmarshal(b, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// isFirstOccurrence reports whether the instance is the first occurrence of the series
+func isFirstOccurrence(series, instance *calendar.Event) bool {
+	if instance.OriginalStartTime == nil {
+		return true
+	}
+	seriesStart, _, err := GetEventTimeRange(series)
+	if err != nil {
+		return false
+	}
+	originalStart, _, err := GetEventTimeRange(&calendar.Event{Start: instance.OriginalStartTime, End: instance.OriginalStartTime})
+	if err != nil {
+		return false
+	}
+	return !originalStart.After(seriesStart)
+}
+
+// mapRRules rewrites the parts of each RRULE line of a recurrence with f
+func mapRRules(recurrence []string, f func(parts []string) []string) []string {
+	result := make([]string, 0, len(recurrence))
+	for _, line := range recurrence {
+		rule, ok := strings.CutPrefix(line, "RRULE:")
+		if !ok {
+			result = append(result, line)
+			continue
+		}
+		result = append(result, "RRULE:"+strings.Join(f(strings.Split(rule, ";")), ";"))
+	}
+	return result
+}
+
+// withoutRRulePart removes the part with the name (e.g. COUNT) from RRULE parts
+func withoutRRulePart(parts []string, name string) []string {
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if !strings.HasPrefix(part, name+"=") {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// rruleCount returns the COUNT of the first RRULE that has one
+func rruleCount(recurrence []string) (int64, bool) {
+	for _, line := range recurrence {
+		rule, ok := strings.CutPrefix(line, "RRULE:")
+		if !ok {
+			continue
+		}
+		for _, part := range strings.Split(rule, ";") {
+			if v, ok := strings.CutPrefix(part, "COUNT="); ok {
+				n, err := strconv.ParseInt(v, 10, 64)
+				if err == nil {
+					return n, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// TruncateRecurrence ends the RRULEs of a recurrence right before the occurrence starting at start
+func TruncateRecurrence(recurrence []string, start *calendar.EventDateTime) ([]string, error) {
+	var until string
+	if start.DateTime != "" {
+		t, err := time.Parse(time.RFC3339, start.DateTime)
+		if err != nil {
+			return nil, err
+		}
+		until = t.Add(-time.Second).UTC().Format("20060102T150405Z")
+	} else {
+		t, err := time.ParseInLocation("2006-01-02", start.Date, parser.GetLocation())
+		if err != nil {
+			return nil, err
+		}
+		until = t.AddDate(0, 0, -1).Format("20060102")
+	}
+	return mapRRules(recurrence, func(parts []string) []string {
+		parts = withoutRRulePart(withoutRRulePart(parts, "COUNT"), "UNTIL")
+		return append(parts, "UNTIL="+until)
+	}), nil
+}
+
+// RemainingRecurrence returns the recurrence continued after done occurrences
+func RemainingRecurrence(recurrence []string, done int64) []string {
+	return mapRRules(recurrence, func(parts []string) []string {
+		for i, part := range parts {
+			if v, ok := strings.CutPrefix(part, "COUNT="); ok {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+					parts[i] = "COUNT=" + strconv.FormatInt(max(n-done, 1), 10)
+				}
+			}
+		}
+		return parts
+	})
+}
+
+// CountInstancesBefore returns the number of occurrences of the series that originally start before t
+func CountInstancesBefore(srv *calendar.Service, calendarID, seriesID string, t time.Time) (int64, error) {
+	var count int64
+	err := srv.Events.Instances(calendarID, seriesID).
+		ShowDeleted(true).
+		TimeMax(t.Format(time.RFC3339)).
+		MaxResults(listEventsPageSize).
+		Pages(context.Background(), func(events *calendar.Events) error {
+			for _, item := range events.Items {
+				if item.OriginalStartTime == nil {
+					continue
+				}
+				start, _, err := GetEventTimeRange(&calendar.Event{Start: item.OriginalStartTime, End: item.OriginalStartTime})
+				if err == nil && start.Before(t) {
+					count++
+				}
+			}
+			return nil
+		})
+	return count, err
+}
+
+// splitSeries returns the series truncated before the instance and a new series continued from the instance
+func splitSeries(srv *calendar.Service, calendarID string, series, instance *calendar.Event) (*calendar.Event, *calendar.Event, error) {
+	truncated, err := CloneEvent(series)
+	if err != nil {
+		return nil, nil, err
+	}
+	truncated.Recurrence, err = TruncateRecurrence(series.Recurrence, instance.OriginalStartTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	following, err := CloneEvent(series)
+	if err != nil {
+		return nil, nil, err
+	}
+	following.Id = ""
+	following.ICalUID = ""
+	following.Etag = ""
+	following.HtmlLink = ""
+	following.Created = ""
+	following.Updated = ""
+	following.Sequence = 0
+	following.Start = seriesDateTime(instance.Start, series.Start)
+	following.End = seriesDateTime(instance.End, series.End)
+	if count, ok := rruleCount(series.Recurrence); ok && count > 0 {
+		start, _, err := GetEventTimeRange(&calendar.Event{Start: instance.OriginalStartTime, End: instance.OriginalStartTime})
+		if err != nil {
+			return nil, nil, err
+		}
+		done, err := CountInstancesBefore(srv, calendarID, series.Id, start)
+		if err != nil {
+			return nil, nil, err
+		}
+		following.Recurrence = RemainingRecurrence(following.Recurrence, done)
+	}
+	return truncated, following, nil
+}
+
+// seriesDateTime copies an instance time and keeps the timezone of the series, which recurring events require
+func seriesDateTime(dt, series *calendar.EventDateTime) *calendar.EventDateTime {
+	result := &calendar.EventDateTime{Date: dt.Date, DateTime: dt.DateTime, TimeZone: dt.TimeZone}
+	if result.DateTime != "" && result.TimeZone == "" {
+		result.TimeZone = series.TimeZone
+	}
+	return result
+}
+
+// eventLocation returns the timezone of the event time, or the location of t when it has none
+func eventLocation(dt *calendar.EventDateTime, t time.Time) *time.Location {
+	if dt != nil && dt.TimeZone != "" {
+		if loc, err := time.LoadLocation(dt.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return t.Location()
+}
+
+// wallClock returns the wall clock time of t in loc as a UTC time, so that differences ignore DST transitions
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// ShiftEventTimes returns the start and end of the event moved by delta of wall clock time with the new length.
+// All-day events are moved by whole days.
+func ShiftEventTimes(event *calendar.Event, delta, length time.Duration) (*calendar.EventDateTime, *calendar.EventDateTime, error) {
+	start, _, err := GetEventTimeRange(event)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event.Start.DateTime == "" {
+		days := int(delta.Round(24*time.Hour) / (24 * time.Hour))
+		lengthDays := max(int(length.Round(24*time.Hour)/(24*time.Hour)), 1)
+		newStart := start.AddDate(0, 0, days)
+		return &calendar.EventDateTime{Date: newStart.Format("2006-01-02")},
+			&calendar.EventDateTime{Date: newStart.AddDate(0, 0, lengthDays).Format("2006-01-02")}, nil
+	}
+	loc := eventLocation(event.Start, start)
+	wall := wallClock(start, loc).Add(delta)
+	newStart := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+	return &calendar.EventDateTime{DateTime: newStart.Format(time.RFC3339), TimeZone: event.Start.TimeZone},
+		&calendar.EventDateTime{DateTime: newStart.Add(length).Format(time.RFC3339), TimeZone: event.Start.TimeZone}, nil
+}
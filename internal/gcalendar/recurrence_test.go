@@ -0,0 +1,228 @@
+package gcalendar
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestTruncateRecurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence []string
+		start      *calendar.EventDateTime
+		want       []string
+	}{
+		{
+			name:       "timed event ends one second before the occurrence in UTC",
+			recurrence: []string{"RRULE:FREQ=WEEKLY;BYDAY=TU"},
+			start:      &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00+09:00", TimeZone: "Asia/Tokyo"},
+			want:       []string{"RRULE:FREQ=WEEKLY;BYDAY=TU;UNTIL=20250610T005959Z"},
+		},
+		{
+			name:       "all-day event ends the day before",
+			recurrence: []string{"RRULE:FREQ=DAILY"},
+			start:      &calendar.EventDateTime{Date: "2025-03-01"},
+			want:       []string{"RRULE:FREQ=DAILY;UNTIL=20250228"},
+		},
+		{
+			name:       "COUNT is replaced with UNTIL",
+			recurrence: []string{"RRULE:FREQ=DAILY;COUNT=10;INTERVAL=2"},
+			start:      &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00Z"},
+			want:       []string{"RRULE:FREQ=DAILY;INTERVAL=2;UNTIL=20250610T095959Z"},
+		},
+		{
+			name:       "earlier UNTIL is replaced",
+			recurrence: []string{"RRULE:FREQ=DAILY;UNTIL=20251231T000000Z"},
+			start:      &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00Z"},
+			want:       []string{"RRULE:FREQ=DAILY;UNTIL=20250610T095959Z"},
+		},
+		{
+			name:       "EXDATE and RDATE lines are kept",
+			recurrence: []string{"EXDATE;TZID=Asia/Tokyo:20250603T100000", "RRULE:FREQ=WEEKLY", "RDATE;VALUE=DATE:20250620"},
+			start:      &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00+09:00"},
+			want:       []string{"EXDATE;TZID=Asia/Tokyo:20250603T100000", "RRULE:FREQ=WEEKLY;UNTIL=20250610T005959Z", "RDATE;VALUE=DATE:20250620"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TruncateRecurrence(tt.recurrence, tt.start)
+			if err != nil {
+				t.Fatalf("TruncateRecurrence() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("TruncateRecurrence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateRecurrenceInvalidStart(t *testing.T) {
+	if _, err := TruncateRecurrence([]string{"RRULE:FREQ=DAILY"}, &calendar.EventDateTime{DateTime: "tomorrow"}); err == nil {
+		t.Error("TruncateRecurrence() error = nil, want error")
+	}
+}
+
+func TestRemainingRecurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence []string
+		done       int64
+		want       []string
+	}{
+		{
+			name:       "COUNT is reduced by the past occurrences",
+			recurrence: []string{"RRULE:FREQ=WEEKLY;COUNT=10"},
+			done:       3,
+			want:       []string{"RRULE:FREQ=WEEKLY;COUNT=7"},
+		},
+		{
+			name:       "at least one occurrence remains",
+			recurrence: []string{"RRULE:FREQ=WEEKLY;COUNT=2"},
+			done:       5,
+			want:       []string{"RRULE:FREQ=WEEKLY;COUNT=1"},
+		},
+		{
+			name:       "rule without COUNT is unchanged",
+			recurrence: []string{"EXDATE:20250603T010000Z", "RRULE:FREQ=DAILY;UNTIL=20251231"},
+			done:       3,
+			want:       []string{"EXDATE:20250603T010000Z", "RRULE:FREQ=DAILY;UNTIL=20251231"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RemainingRecurrence(tt.recurrence, tt.done)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("RemainingRecurrence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sameDateTime(a, b *calendar.EventDateTime) bool {
+	return a.Date == b.Date && a.DateTime == b.DateTime && a.TimeZone == b.TimeZone
+}
+
+func TestShiftEventTimes(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     *calendar.EventDateTime
+		end       *calendar.EventDateTime
+		delta     time.Duration
+		length    time.Duration
+		wantStart *calendar.EventDateTime
+		wantEnd   *calendar.EventDateTime
+	}{
+		{
+			name:      "timed event moved and lengthened",
+			start:     &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00+09:00", TimeZone: "Asia/Tokyo"},
+			end:       &calendar.EventDateTime{DateTime: "2025-06-10T11:00:00+09:00", TimeZone: "Asia/Tokyo"},
+			delta:     90 * time.Minute,
+			length:    2 * time.Hour,
+			wantStart: &calendar.EventDateTime{DateTime: "2025-06-10T11:30:00+09:00", TimeZone: "Asia/Tokyo"},
+			wantEnd:   &calendar.EventDateTime{DateTime: "2025-06-10T13:30:00+09:00", TimeZone: "Asia/Tokyo"},
+		},
+		{
+			name:      "next day across spring forward keeps the wall clock time",
+			start:     &calendar.EventDateTime{DateTime: "2025-03-29T10:00:00+01:00", TimeZone: "Europe/Berlin"},
+			end:       &calendar.EventDateTime{DateTime: "2025-03-29T11:00:00+01:00", TimeZone: "Europe/Berlin"},
+			delta:     24 * time.Hour,
+			length:    time.Hour,
+			wantStart: &calendar.EventDateTime{DateTime: "2025-03-30T10:00:00+02:00", TimeZone: "Europe/Berlin"},
+			wantEnd:   &calendar.EventDateTime{DateTime: "2025-03-30T11:00:00+02:00", TimeZone: "Europe/Berlin"},
+		},
+		{
+			name:      "previous day across fall back keeps the wall clock time",
+			start:     &calendar.EventDateTime{DateTime: "2025-10-27T09:00:00+01:00", TimeZone: "Europe/Berlin"},
+			end:       &calendar.EventDateTime{DateTime: "2025-10-27T09:30:00+01:00", TimeZone: "Europe/Berlin"},
+			delta:     -24 * time.Hour,
+			length:    30 * time.Minute,
+			wantStart: &calendar.EventDateTime{DateTime: "2025-10-26T09:00:00+01:00", TimeZone: "Europe/Berlin"},
+			wantEnd:   &calendar.EventDateTime{DateTime: "2025-10-26T09:30:00+01:00", TimeZone: "Europe/Berlin"},
+		},
+		{
+			name:      "all-day event is rounded to whole days",
+			start:     &calendar.EventDateTime{Date: "2025-06-10"},
+			end:       &calendar.EventDateTime{Date: "2025-06-11"},
+			delta:     47 * time.Hour,
+			length:    24 * time.Hour,
+			wantStart: &calendar.EventDateTime{Date: "2025-06-12"},
+			wantEnd:   &calendar.EventDateTime{Date: "2025-06-13"},
+		},
+		{
+			name:      "all-day event lasts at least one day",
+			start:     &calendar.EventDateTime{Date: "2025-06-10"},
+			end:       &calendar.EventDateTime{Date: "2025-06-12"},
+			delta:     -24 * time.Hour,
+			length:    time.Hour,
+			wantStart: &calendar.EventDateTime{Date: "2025-06-09"},
+			wantEnd:   &calendar.EventDateTime{Date: "2025-06-10"},
+		},
+		{
+			name:      "all-day event across month end",
+			start:     &calendar.EventDateTime{Date: "2025-01-31"},
+			end:       &calendar.EventDateTime{Date: "2025-02-01"},
+			delta:     24 * time.Hour,
+			length:    48 * time.Hour,
+			wantStart: &calendar.EventDateTime{Date: "2025-02-01"},
+			wantEnd:   &calendar.EventDateTime{Date: "2025-02-03"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ShiftEventTimes(&calendar.Event{Start: tt.start, End: tt.end}, tt.delta, tt.length)
+			if err != nil {
+				t.Fatalf("ShiftEventTimes() error = %v", err)
+			}
+			if !sameDateTime(start, tt.wantStart) {
+				t.Errorf("start = %+v, want %+v", *start, *tt.wantStart)
+			}
+			if !sameDateTime(end, tt.wantEnd) {
+				t.Errorf("end = %+v, want %+v", *end, *tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestIsFirstOccurrence(t *testing.T) {
+	series := &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: "2025-06-03T10:00:00+09:00", TimeZone: "Asia/Tokyo"},
+		End:   &calendar.EventDateTime{DateTime: "2025-06-03T11:00:00+09:00", TimeZone: "Asia/Tokyo"},
+	}
+	tests := []struct {
+		name     string
+		original *calendar.EventDateTime
+		want     bool
+	}{
+		{
+			name:     "not an instance",
+			original: nil,
+			want:     true,
+		},
+		{
+			name:     "first occurrence",
+			original: &calendar.EventDateTime{DateTime: "2025-06-03T10:00:00+09:00"},
+			want:     true,
+		},
+		{
+			name:     "first occurrence in another offset",
+			original: &calendar.EventDateTime{DateTime: "2025-06-03T01:00:00Z"},
+			want:     true,
+		},
+		{
+			name:     "later occurrence",
+			original: &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00+09:00"},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &calendar.Event{OriginalStartTime: tt.original}
+			if got := isFirstOccurrence(series, instance); got != tt.want {
+				t.Errorf("isFirstOccurrence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,7 +15,7 @@ func GetReferenceMappedEvents(srv *calendar.Service, since, until string, refIDs
 		var err error
 		refEventMap, err = GetUnionMappedEvents(srv, ids, since, until)
 		if err != nil {
-			log.Fatalf("Unable to retrieve events from ref calendars: %v", err)
+			log.Printf("Warning: some reference calendars could not be retrieved: %v", err)
 		}
 	}
 	return refEventMap, nil
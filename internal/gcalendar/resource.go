@@ -1,7 +1,13 @@
 package gcalendar
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
 	admdir "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/calendar/v3"
 )
 
 // ListAllCalendarResourcesWithPagination fetches all calendar resources with pagination
@@ -39,3 +45,97 @@ func FilterCalendarResourcesByBuildingId(resources []*admdir.CalendarResource, b
 	}
 	return filtered
 }
+
+// GetCalendarResourceFeatures returns the feature names of a calendar resource
+func GetCalendarResourceFeatures(resource *admdir.CalendarResource) []string {
+	if resource.FeatureInstances == nil {
+		return nil
+	}
+	// FeatureInstances is decoded as a generic value, so round-trip it through JSON
+	b, err := json.Marshal(resource.FeatureInstances)
+	if err != nil {
+		return nil
+	}
+	var instances []admdir.FeatureInstance
+	if err := json.Unmarshal(b, &instances); err != nil {
+		return nil
+	}
+	features := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Feature != nil && instance.Feature.Name != "" {
+			features = append(features, instance.Feature.Name)
+		}
+	}
+	return features
+}
+
+// FilterCalendarResourcesByCapacity filters calendar resources that can hold at least capacity people
+func FilterCalendarResourcesByCapacity(resources []*admdir.CalendarResource, capacity int64) []*admdir.CalendarResource {
+	if capacity <= 0 {
+		return resources
+	}
+	filtered := make([]*admdir.CalendarResource, 0)
+	for _, entry := range resources {
+		if entry.Capacity >= capacity {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// FilterCalendarResourcesByFeatures filters calendar resources that have all of the features (case insensitive)
+func FilterCalendarResourcesByFeatures(resources []*admdir.CalendarResource, features []string) []*admdir.CalendarResource {
+	if len(features) == 0 {
+		return resources
+	}
+	filtered := make([]*admdir.CalendarResource, 0)
+	for _, entry := range resources {
+		have := GetCalendarResourceFeatures(entry)
+		ok := true
+		for _, want := range features {
+			if !slices.ContainsFunc(have, func(h string) bool { return strings.EqualFold(h, want) }) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// FilterFreeCalendarResources filters calendar resources that have no busy period between since and until
+func FilterFreeCalendarResources(srv *calendar.Service, resources []*admdir.CalendarResource, since, until string) ([]*admdir.CalendarResource, error) {
+	ids := make([]string, 0, len(resources))
+	for _, entry := range resources {
+		if entry.ResourceEmail != "" {
+			ids = append(ids, entry.ResourceEmail)
+		}
+	}
+	calendars, err := QueryFreeBusy(srv, ids, since, until)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*admdir.CalendarResource, 0)
+	for _, entry := range resources {
+		cal, ok := calendars[entry.ResourceEmail]
+		if !ok || len(cal.Errors) > 0 || len(cal.Busy) > 0 {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered, nil
+}
+
+// FindCalendarResource finds a calendar resource by name, generated name or email (case insensitive)
+func FindCalendarResource(resources []*admdir.CalendarResource, name string) (*admdir.CalendarResource, error) {
+	for _, entry := range resources {
+		if strings.EqualFold(entry.ResourceName, name) ||
+			strings.EqualFold(entry.GeneratedResourceName, name) ||
+			strings.EqualFold(entry.ResourceEmail, name) {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("resource not found: %s", name)
+}
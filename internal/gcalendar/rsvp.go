@@ -0,0 +1,51 @@
+package gcalendar
+
+import (
+	"fmt"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+var ResponseValues = []string{"accept", "decline", "tentative"}
+
+// ParseResponse converts an RSVP answer (accept, decline or tentative) to the attendee response status
+func ParseResponse(s string) (string, error) {
+	switch s {
+	case "accept", "accepted", "yes":
+		return "accepted", nil
+	case "decline", "declined", "no":
+		return "declined", nil
+	case "tentative", "maybe":
+		return "tentative", nil
+	}
+	return "", fmt.Errorf("invalid response: %s (must be one of %v)", s, ResponseValues)
+}
+
+// GetSelfAttendee returns the attendee entry of the user, or nil when the user is not invited
+func GetSelfAttendee(event *calendar.Event) *calendar.EventAttendee {
+	for _, attendee := range event.Attendees {
+		if attendee.Self {
+			return attendee
+		}
+	}
+	return nil
+}
+
+// RespondToEvent sets the user's response status and comment of the event
+func RespondToEvent(srv *calendar.Service, calendarID string, event *calendar.Event, status, comment, sendUpdates string) (*calendar.Event, error) {
+	if GetSelfAttendee(event) == nil {
+		return nil, fmt.Errorf("you are not an attendee of %s", event.Id)
+	}
+	attendees := make([]*calendar.EventAttendee, 0, len(event.Attendees))
+	for _, attendee := range event.Attendees {
+		a := *attendee
+		if a.Self {
+			a.ResponseStatus = status
+			if comment != "" {
+				a.Comment = comment
+			}
+		}
+		attendees = append(attendees, &a)
+	}
+	return srv.Events.Patch(calendarID, event.Id, &calendar.Event{Attendees: attendees}).SendUpdates(sendUpdates).Do()
+}
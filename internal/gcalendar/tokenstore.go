@@ -0,0 +1,367 @@
+package gcalendar
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/srz-zumix/gali/internal/config"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const keyringService = "gali"
+
+// tokenStore saves the OAuth tokens of profiles
+type tokenStore interface {
+	// load returns an error wrapping os.ErrNotExist when the profile has no token
+	load(profile string) (*savedToken, error)
+	save(profile string, token *savedToken) error
+	remove(profile string) error
+	exists(profile string) bool
+	// location describes where the token is stored
+	location(profile string) string
+}
+
+var currentTokenStore tokenStore = fileTokenStore{}
+
+// SetTokenStore selects the backend that stores OAuth tokens (file, keyring or encrypted-file; empty for file).
+// When the OS keyring is unavailable (e.g. no Secret Service on a headless Linux), keyring falls back to encrypted-file.
+func SetTokenStore(name string) error {
+	switch name {
+	case "", config.TokenStoreFile:
+		currentTokenStore = fileTokenStore{}
+	case config.TokenStoreKeyring:
+		if err := checkKeyring(); err != nil {
+			log.Printf("Warning: the OS keyring is unavailable (%v), storing tokens in the encrypted file instead", err)
+			currentTokenStore = &encryptedFileTokenStore{}
+			return nil
+		}
+		currentTokenStore = keyringTokenStore{}
+	case config.TokenStoreEncryptedFile:
+		currentTokenStore = &encryptedFileTokenStore{}
+	default:
+		return fmt.Errorf("invalid token store: %s (must be one of %v)", name, config.TokenStoreValues)
+	}
+	return nil
+}
+
+// loadToken reads the token of the profile.
+// A plaintext token file is moved to the selected store the first time it is read.
+func loadToken(profile string) (*savedToken, error) {
+	tok, err := currentTokenStore.load(profile)
+	if _, isFile := currentTokenStore.(fileTokenStore); isFile || !errors.Is(err, os.ErrNotExist) {
+		return tok, err
+	}
+	plain, perr := (fileTokenStore{}).load(profile)
+	if perr != nil {
+		return nil, err
+	}
+	if err := currentTokenStore.save(profile, plain); err != nil {
+		return nil, fmt.Errorf("unable to migrate token to %s: %w", currentTokenStore.location(profile), err)
+	}
+	if err := (fileTokenStore{}).remove(profile); err != nil {
+		log.Printf("Warning: unable to delete plaintext token file: %v", err)
+	}
+	log.Printf("Migrated the token of profile %s to %s", profile, currentTokenStore.location(profile))
+	return plain, nil
+}
+
+func storeToken(profile string, token *savedToken) error {
+	return currentTokenStore.save(profile, token)
+}
+
+func deleteToken(profile string) error {
+	return currentTokenStore.remove(profile)
+}
+
+// HasToken reports whether the profile has a saved token (including a plaintext token file not migrated yet)
+func HasToken(profile string) bool {
+	return currentTokenStore.exists(profile) || (fileTokenStore{}).exists(profile)
+}
+
+func fileExists(path string, err error) bool {
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// GetTokenLocation describes where the token of the profile is stored
+func GetTokenLocation(profile string) string {
+	return currentTokenStore.location(profile)
+}
+
+// fileTokenStore stores tokens as plaintext JSON files readable only by the user
+type fileTokenStore struct{}
+
+func (fileTokenStore) load(profile string) (*savedToken, error) {
+	path, err := GetTokenCacheFile(profile)
+	if err != nil {
+		return nil, err
+	}
+	return tokenFromFile(path)
+}
+
+func (fileTokenStore) save(profile string, token *savedToken) error {
+	path, err := GetTokenCacheFile(profile)
+	if err != nil {
+		return err
+	}
+	return saveToken(path, token)
+}
+
+func (fileTokenStore) remove(profile string) error {
+	path, err := GetTokenCacheFile(profile)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (fileTokenStore) exists(profile string) bool {
+	return fileExists(GetTokenCacheFile(profile))
+}
+
+func (fileTokenStore) location(profile string) string {
+	path, err := GetTokenCacheFile(profile)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// keyringTokenStore stores tokens in the OS keyring (Secret Service on Linux)
+type keyringTokenStore struct{}
+
+func (keyringTokenStore) load(profile string) (*savedToken, error) {
+	secret, err := keyring.Get(keyringService, profile)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %v", os.ErrNotExist, err)
+		}
+		return nil, fmt.Errorf("unable to read keyring: %w", err)
+	}
+	tok := &savedToken{}
+	if err := json.Unmarshal([]byte(secret), tok); err != nil {
+		return nil, fmt.Errorf("unable to decode token from keyring: %w", err)
+	}
+	return tok, nil
+}
+
+func (keyringTokenStore) save(profile string, token *savedToken) error {
+	b, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("unable to encode token: %w", err)
+	}
+	if err := keyring.Set(keyringService, profile, string(b)); err != nil {
+		return fmt.Errorf("unable to write keyring: %w", err)
+	}
+	return nil
+}
+
+func (keyringTokenStore) remove(profile string) error {
+	err := keyring.Delete(keyringService, profile)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("%w: %v", os.ErrNotExist, err)
+	}
+	return err
+}
+
+func (keyringTokenStore) exists(profile string) bool {
+	_, err := keyring.Get(keyringService, profile)
+	return err == nil
+}
+
+func (keyringTokenStore) location(profile string) string {
+	return "keyring (" + keyringService + "/" + profile + ")"
+}
+
+// checkKeyring returns an error when the OS keyring cannot be accessed
+func checkKeyring() error {
+	_, err := keyring.Get(keyringService, config.DefaultProfile)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// encryptedFileTokenStore stores tokens in files encrypted with AES-GCM.
+// The key is derived with scrypt from GALI_TOKEN_PASSPHRASE or a passphrase entered in the terminal.
+type encryptedFileTokenStore struct {
+	once       sync.Once
+	passphrase []byte
+	err        error
+}
+
+const (
+	encryptedTokenMagic = "GALI1"
+	encryptedSaltSize   = 16
+)
+
+func encryptedTokenFile(profile string) (string, error) {
+	path, err := GetTokenCacheFile(profile)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".enc", nil
+}
+
+// getPassphrase returns the passphrase, asking for it once (twice with confirmation when creating a file)
+func (s *encryptedFileTokenStore) getPassphrase(confirm bool) ([]byte, error) {
+	s.once.Do(func() {
+		if p := os.Getenv("GALI_TOKEN_PASSPHRASE"); p != "" {
+			s.passphrase = []byte(p)
+			return
+		}
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			s.err = fmt.Errorf("set GALI_TOKEN_PASSPHRASE to use the encrypted token file without a terminal")
+			return
+		}
+		fmt.Fprint(os.Stderr, "Token passphrase: ") // nolint
+		p, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr) // nolint
+		if err != nil {
+			s.err = fmt.Errorf("unable to read passphrase: %w", err)
+			return
+		}
+		if confirm {
+			fmt.Fprint(os.Stderr, "Confirm token passphrase: ") // nolint
+			again, err := term.ReadPassword(fd)
+			fmt.Fprintln(os.Stderr) // nolint
+			if err != nil {
+				s.err = fmt.Errorf("unable to read passphrase: %w", err)
+				return
+			}
+			if !bytes.Equal(p, again) {
+				s.err = fmt.Errorf("passphrases do not match")
+				return
+			}
+		}
+		if len(p) == 0 {
+			s.err = fmt.Errorf("empty passphrase")
+			return
+		}
+		s.passphrase = p
+	})
+	return s.passphrase, s.err
+}
+
+func newTokenCipher(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *encryptedFileTokenStore) load(profile string) (*savedToken, error) {
+	path, err := encryptedTokenFile(profile)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rest, ok := bytes.CutPrefix(b, []byte(encryptedTokenMagic))
+	if !ok || len(rest) < encryptedSaltSize {
+		return nil, fmt.Errorf("invalid encrypted token file: %s", path)
+	}
+	passphrase, err := s.getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	salt, rest := rest[:encryptedSaltSize], rest[encryptedSaltSize:]
+	aead, err := newTokenCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid encrypted token file: %s", path)
+	}
+	nonce, data := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, data, []byte(encryptedTokenMagic))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt token (wrong passphrase?)")
+	}
+	tok := &savedToken{}
+	if err := json.Unmarshal(plain, tok); err != nil {
+		return nil, fmt.Errorf("unable to decode token: %w", err)
+	}
+	return tok, nil
+}
+
+func (s *encryptedFileTokenStore) save(profile string, token *savedToken) error {
+	path, err := encryptedTokenFile(profile)
+	if err != nil {
+		return err
+	}
+	_, statErr := os.Stat(path)
+	passphrase, err := s.getPassphrase(os.IsNotExist(statErr))
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("unable to encode token: %w", err)
+	}
+	salt := make([]byte, encryptedSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := newTokenCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	out := append([]byte(encryptedTokenMagic), salt...)
+	out = append(out, nonce...)
+	out = aead.Seal(out, nonce, plain, []byte(encryptedTokenMagic))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("unable to create token cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, out, 0600); err != nil {
+		return fmt.Errorf("unable to write token file: %w", err)
+	}
+	return nil
+}
+
+func (s *encryptedFileTokenStore) remove(profile string) error {
+	path, err := encryptedTokenFile(profile)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (s *encryptedFileTokenStore) exists(profile string) bool {
+	return fileExists(encryptedTokenFile(profile))
+}
+
+func (s *encryptedFileTokenStore) location(profile string) string {
+	path, err := encryptedTokenFile(profile)
+	if err != nil {
+		return ""
+	}
+	return path
+}
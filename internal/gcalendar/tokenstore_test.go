@@ -0,0 +1,156 @@
+package gcalendar
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/srz-zumix/gali/internal/config"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/oauth2"
+)
+
+// useTempTokenCacheDir stores the token files of the test in a temporary directory
+func useTempTokenCacheDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	orig := tokenCacheDir
+	tokenCacheDir = func() (string, error) { return dir, nil }
+	origStore := currentTokenStore
+	t.Cleanup(func() {
+		tokenCacheDir = orig
+		currentTokenStore = origStore
+	})
+	return dir
+}
+
+func testToken() *savedToken {
+	return &savedToken{
+		Token: oauth2.Token{
+			AccessToken:  "access-token",
+			RefreshToken: "refresh-token",
+			TokenType:    "Bearer",
+		},
+		Scopes: GetGaliScope(),
+	}
+}
+
+func assertToken(t *testing.T, got, want *savedToken) {
+	t.Helper()
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || !slices.Equal(got.Scopes, want.Scopes) {
+		t.Errorf("token = %+v, want %+v", got, want)
+	}
+}
+
+func TestEncryptedFileTokenStore(t *testing.T) {
+	useTempTokenCacheDir(t)
+	t.Setenv("GALI_TOKEN_PASSPHRASE", "secret")
+
+	store := &encryptedFileTokenStore{}
+	if _, err := store.load("work"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("load before save: err = %v, want os.ErrNotExist", err)
+	}
+	if store.exists("work") {
+		t.Fatal("exists before save = true")
+	}
+	want := testToken()
+	if err := store.save("work", want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if !store.exists("work") {
+		t.Fatal("exists after save = false")
+	}
+
+	path, err := encryptedTokenFile("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte(want.RefreshToken)) {
+		t.Error("token file contains the plaintext refresh token")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("token file mode = %v (%v), want 0600", info.Mode().Perm(), err)
+	}
+
+	// A new store reads the passphrase again
+	got, err := (&encryptedFileTokenStore{}).load("work")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	assertToken(t, got, want)
+
+	t.Setenv("GALI_TOKEN_PASSPHRASE", "wrong")
+	if _, err := (&encryptedFileTokenStore{}).load("work"); err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Errorf("load with wrong passphrase: err = %v, want a decryption error", err)
+	}
+}
+
+func TestLoadTokenMigratesPlaintextFile(t *testing.T) {
+	useTempTokenCacheDir(t)
+	t.Setenv("GALI_TOKEN_PASSPHRASE", "secret")
+
+	want := testToken()
+	if err := (fileTokenStore{}).save(config.DefaultProfile, want); err != nil {
+		t.Fatalf("save plaintext: %v", err)
+	}
+	if err := SetTokenStore(config.TokenStoreEncryptedFile); err != nil {
+		t.Fatal(err)
+	}
+	if !HasToken(config.DefaultProfile) {
+		t.Fatal("HasToken before migration = false")
+	}
+
+	got, err := loadToken(config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("loadToken: %v", err)
+	}
+	assertToken(t, got, want)
+	if (fileTokenStore{}).exists(config.DefaultProfile) {
+		t.Error("plaintext token file was not deleted")
+	}
+	if !currentTokenStore.exists(config.DefaultProfile) {
+		t.Fatal("token was not saved to the encrypted file")
+	}
+
+	got, err = loadToken(config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("loadToken after migration: %v", err)
+	}
+	assertToken(t, got, want)
+}
+
+func TestLoadTokenNotExist(t *testing.T) {
+	useTempTokenCacheDir(t)
+	if err := SetTokenStore(config.TokenStoreEncryptedFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadToken(config.DefaultProfile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("loadToken: err = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestSetTokenStoreKeyringFallback(t *testing.T) {
+	defer func() { currentTokenStore = fileTokenStore{} }()
+
+	keyring.MockInitWithError(errors.New("no Secret Service"))
+	if err := SetTokenStore(config.TokenStoreKeyring); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := currentTokenStore.(*encryptedFileTokenStore); !ok {
+		t.Errorf("token store = %T, want *encryptedFileTokenStore", currentTokenStore)
+	}
+
+	keyring.MockInit()
+	if err := SetTokenStore(config.TokenStoreKeyring); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := currentTokenStore.(keyringTokenStore); !ok {
+		t.Errorf("token store = %T, want keyringTokenStore", currentTokenStore)
+	}
+}
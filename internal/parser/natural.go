@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventText is the result of parsing a natural-language event description
+type EventText struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+	AllDay  bool
+}
+
+var (
+	clockPattern      = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	clockRangePattern = regexp.MustCompile(`^([0-9:apm]+)-([0-9:apm]+)$`)
+	durationPattern   = regexp.MustCompile(`^(\d+(?:\.\d+)?)(m|min|mins|h|hr|hrs|hours?)$`)
+	monthDayPattern   = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})$`)
+)
+
+// connectives are dropped when they directly precede a date, time or duration
+var connectives = map[string]bool{
+	"at":   true,
+	"on":   true,
+	"for":  true,
+	"from": true,
+}
+
+// parseClockToken parses 12:00, 9am, 3:30pm or 15 (only with am/pm or minutes)
+func parseClockToken(s string) (time.Duration, bool) {
+	m := clockPattern.FindStringSubmatch(strings.ToLower(s))
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if m[3] != "" && (hour == 0 || hour > 12) {
+		return 0, false
+	}
+	switch m[3] {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+	// 24:00 is the end of the day, but 24:30 is not a time
+	if hour > 24 || minute > 59 || (hour == 24 && minute > 0) {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+func parseDurationToken(s string) (time.Duration, bool) {
+	m := durationPattern.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	if strings.HasPrefix(m[2], "m") {
+		return time.Duration(n * float64(time.Minute)), true
+	}
+	return time.Duration(n * float64(time.Hour)), true
+}
+
+// parseDateToken parses a date expression accepted by ParseDate or M/D
+func parseDateToken(s string, loc *time.Location) (time.Time, bool) {
+	if m := monthDayPattern.FindStringSubmatch(s); m != nil {
+		month, _ := strconv.Atoi(m[1])
+		day, _ := strconv.Atoi(m[2])
+		today := Today(loc)
+		t := time.Date(today.Year(), time.Month(month), day, 0, 0, 0, 0, loc)
+		if t.Before(today) {
+			t = t.AddDate(1, 0, 0)
+		}
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, true
+	}
+	return parseRelativeDate(s, loc)
+}
+
+// isSchedulingWord reports whether the word is a connective, date, time or duration
+func isSchedulingWord(w string, loc *time.Location) bool {
+	lower := strings.ToLower(w)
+	if connectives[lower] {
+		return true
+	}
+	if _, ok := parseClockToken(w); ok {
+		return true
+	}
+	if m := clockRangePattern.FindStringSubmatch(lower); m != nil {
+		return true
+	}
+	if _, ok := parseDurationToken(w); ok {
+		return true
+	}
+	_, ok := parseDateToken(w, loc)
+	return ok
+}
+
+// isWeekdayInTitle reports whether a bare weekday word (e.g. "Sun" or "Sat") is part of the title.
+// It is a date only after "on" or when only dates, times and durations follow it.
+func isWeekdayInTitle(words []string, i int, loc *time.Location) bool {
+	if _, ok := weekdays[strings.ToLower(words[i])]; !ok {
+		return false
+	}
+	if i > 0 && strings.EqualFold(words[i-1], "on") {
+		return false
+	}
+	for _, w := range words[i+1:] {
+		if !isSchedulingWord(w, loc) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEventText parses a natural-language event such as "Lunch with Sato tomorrow 12:00".
+// It recognizes one date expression (see ParseDate, or M/D), a start time or time range
+// (12:00, 3pm, 10:00-11:30) and a duration (1h, 30min). The remaining words become the summary.
+// Without a time the event is all-day; without an end the event lasts defaultDuration.
+func ParseEventText(text string, defaultDuration time.Duration) (*EventText, error) {
+	loc := GetLocation()
+	words := strings.Fields(text)
+	used := make([]bool, len(words))
+
+	var date time.Time
+	hasDate := false
+	var startClock, endClock time.Duration
+	hasStart, hasEnd := false, false
+	var duration time.Duration
+	hasDuration := false
+
+	markUsed := func(i, n int) {
+		for j := i; j < i+n; j++ {
+			used[j] = true
+		}
+		if i > 0 && connectives[strings.ToLower(words[i-1])] {
+			used[i-1] = true
+		}
+	}
+
+	for i := 0; i < len(words); i++ {
+		if used[i] {
+			continue
+		}
+		// Two word date expressions such as "next monday" take precedence
+		if !hasDate && i+1 < len(words) {
+			if t, ok := parseDateToken(words[i]+" "+words[i+1], loc); ok {
+				date, hasDate = t, true
+				markUsed(i, 2)
+				i++
+				continue
+			}
+		}
+		w := words[i]
+		if !hasStart {
+			if m := clockRangePattern.FindStringSubmatch(strings.ToLower(w)); m != nil {
+				s, ok1 := parseClockToken(m[1])
+				e, ok2 := parseClockToken(m[2])
+				if ok1 && ok2 {
+					startClock, endClock, hasStart, hasEnd = s, e, true, true
+					markUsed(i, 1)
+					continue
+				}
+			}
+			if c, ok := parseClockToken(w); ok {
+				startClock, hasStart = c, true
+				markUsed(i, 1)
+				continue
+			}
+		}
+		if !hasDuration {
+			if d, ok := parseDurationToken(w); ok {
+				duration, hasDuration = d, true
+				markUsed(i, 1)
+				continue
+			}
+		}
+		if !hasDate && !isWeekdayInTitle(words, i, loc) {
+			if t, ok := parseDateToken(w, loc); ok {
+				date, hasDate = t, true
+				markUsed(i, 1)
+				continue
+			}
+		}
+	}
+
+	summary := []string{}
+	for i, w := range words {
+		if !used[i] {
+			summary = append(summary, w)
+		}
+	}
+	if len(summary) == 0 {
+		return nil, fmt.Errorf("no event title in %q", text)
+	}
+	if !hasDate {
+		date = Today(loc)
+	}
+
+	ev := &EventText{Summary: strings.Join(summary, " ")}
+	if !hasStart {
+		ev.AllDay = true
+		ev.Start = date
+		ev.End = NextDay(date)
+		return ev, nil
+	}
+	ev.Start = atClock(date, startClock)
+	switch {
+	case hasEnd:
+		ev.End = atClock(date, endClock)
+		if !ev.End.After(ev.Start) {
+			ev.End = ev.End.AddDate(0, 0, 1)
+		}
+	case hasDuration:
+		ev.End = ev.Start.Add(duration)
+	default:
+		ev.End = ev.Start.Add(defaultDuration)
+	}
+	return ev, nil
+}
+
+// atClock returns the wall clock time on the date (DST safe)
+func atClock(date time.Time, clock time.Duration) time.Time {
+	h := int(clock / time.Hour)
+	m := int((clock % time.Hour) / time.Minute)
+	return time.Date(date.Year(), date.Month(), date.Day(), h, m, 0, 0, date.Location())
+}
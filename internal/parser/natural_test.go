@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEventText(t *testing.T) {
+	if err := SetTimeZone("Asia/Tokyo"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetTimeZone("") }()
+	loc := GetLocation()
+	// Wednesday
+	now = func() time.Time { return time.Date(2025, 6, 11, 15, 0, 0, 0, loc) }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		text      string
+		summary   string
+		wantStart string
+		wantEnd   string
+		allDay    bool
+	}{
+		{"Lunch with Sato tomorrow 12:00", "Lunch with Sato", "2025-06-12T12:00:00+09:00", "2025-06-12T13:00:00+09:00", false},
+		{"Review next friday at 3pm for 30min", "Review", "2025-06-13T15:00:00+09:00", "2025-06-13T15:30:00+09:00", false},
+		{"Standup 10:00-10:15", "Standup", "2025-06-11T10:00:00+09:00", "2025-06-11T10:15:00+09:00", false},
+		{"Offsite on 7/1", "Offsite", "2025-07-01T00:00:00+09:00", "2025-07-02T00:00:00+09:00", true},
+		{"Release 2025-06-30 9am 2h", "Release", "2025-06-30T09:00:00+09:00", "2025-06-30T11:00:00+09:00", false},
+		{"Team lunch fri 12:00", "Team lunch", "2025-06-13T12:00:00+09:00", "2025-06-13T13:00:00+09:00", false},
+		{"Sun protection briefing tomorrow 10:00", "Sun protection briefing", "2025-06-12T10:00:00+09:00", "2025-06-12T11:00:00+09:00", false},
+		{"Sat Nav review on fri 3pm", "Sat Nav review", "2025-06-13T15:00:00+09:00", "2025-06-13T16:00:00+09:00", false},
+		{"Wed planning", "Wed planning", "2025-06-11T00:00:00+09:00", "2025-06-12T00:00:00+09:00", true},
+		{"Deploy window 23:00-24:00", "Deploy window", "2025-06-11T23:00:00+09:00", "2025-06-12T00:00:00+09:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseEventText(tt.text, time.Hour)
+			if err != nil {
+				t.Fatalf("ParseEventText(%q) error = %v", tt.text, err)
+			}
+			if got.Summary != tt.summary {
+				t.Errorf("Summary = %q, want %q", got.Summary, tt.summary)
+			}
+			if got.AllDay != tt.allDay {
+				t.Errorf("AllDay = %v, want %v", got.AllDay, tt.allDay)
+			}
+			if s := got.Start.Format(time.RFC3339); s != tt.wantStart {
+				t.Errorf("Start = %v, want %v", s, tt.wantStart)
+			}
+			if e := got.End.Format(time.RFC3339); e != tt.wantEnd {
+				t.Errorf("End = %v, want %v", e, tt.wantEnd)
+			}
+		})
+	}
+
+	if _, err := ParseEventText("tomorrow 12:00", time.Hour); err == nil {
+		t.Error("ParseEventText without title should fail")
+	}
+}
+
+func TestParseClockToken(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"12:00", 12 * time.Hour, true},
+		{"9am", 9 * time.Hour, true},
+		{"12am", 0, true},
+		{"3:30pm", 15*time.Hour + 30*time.Minute, true},
+		{"24:00", 24 * time.Hour, true},
+		{"24:30", 0, false},
+		{"25:00", 0, false},
+		{"13pm", 0, false},
+		{"0am", 0, false},
+		{"9:60", 0, false},
+		{"15", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := parseClockToken(tt.in)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseClockToken(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -1,12 +1,30 @@
 package parser
 
 import (
+	"fmt"
 	"os"
 	"time"
 )
 
-// getTimeZone returns the timezone from TZ env or Asia/Tokyo as default
+// timeZone overrides the TZ env when set by SetTimeZone
+var timeZone string
+
+// SetTimeZone sets the timezone used to interpret and display dates (empty to use TZ env)
+func SetTimeZone(tz string) error {
+	if tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("invalid timezone: %s", tz)
+		}
+	}
+	timeZone = tz
+	return nil
+}
+
+// getTimeZone returns the timezone set by SetTimeZone, TZ env or Asia/Tokyo as default
 func getTimeZone() string {
+	if timeZone != "" {
+		return timeZone
+	}
 	tz := os.Getenv("TZ")
 	if tz == "" {
 		tz = "Asia/Tokyo"
@@ -14,17 +32,72 @@ func getTimeZone() string {
 	return tz
 }
 
-// ParseDate parses date string (RFC3339 or YYYY-MM-DD) with timezone
-func ParseDate(s string) (time.Time, error) {
+// GetLocation returns the location used to interpret dates
+func GetLocation() *time.Location {
 	tz, err := time.LoadLocation(getTimeZone())
 	if err != nil {
 		tz = time.FixedZone("Asia/Tokyo", 9*60*60)
 	}
-	return time.ParseInLocation("2006-01-02", s, tz)
+	return tz
+}
+
+// ParseDate parses date string (RFC3339, YYYY-MM-DD or relative expression) with timezone
+// Relative expressions are today, tomorrow, yesterday, +3d, -1w, +2m, +1y, monday, next friday,
+// this/next/last week|month|quarter|year and bow/eow, bom/eom, boq/eoq, boy/eoy.
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	loc := GetLocation()
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, nil
+	}
+	if t, ok := parseRelativeDate(s, loc); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s (expected RFC3339, YYYY-MM-DD or relative expression such as today, +3d, monday, next week, eom)", s)
+}
+
+// isTimestamp reports whether s is a RFC3339 timestamp rather than a date
+func isTimestamp(s string) bool {
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
+
+// ParseDateTime parses date time string (RFC3339, YYYY-MM-DD HH:MM or any date accepted by ParseDate) with timezone
+func ParseDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, GetLocation()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := ParseDate(s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date time: %s (expected RFC3339, YYYY-MM-DD HH:MM or date expression)", s)
 }
 
+// ParseClock parses time of day string (HH:MM) and returns the offset from midnight
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		if s == "24:00" {
+			return 24 * time.Hour, nil
+		}
+		return 0, fmt.Errorf("invalid time of day: %s (expected HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ParseSinceUntil converts --since and --until to RFC3339 query bounds.
+// A date for until is inclusive: the bound is the exclusive start of the next day, computed with
+// calendar arithmetic so that days shortened or lengthened by DST transitions are fully covered.
+// A RFC3339 timestamp for until is used as is.
 func ParseSinceUntil(since, until string) (string, string, error) {
-	today := time.Now().Format("2006-01-02")
+	today := Today(GetLocation()).Format("2006-01-02")
 	if since == "" && until == "" {
 		since = today
 		until = today
@@ -38,12 +111,19 @@ func ParseSinceUntil(since, until string) (string, string, error) {
 		since = sinceTime.Format(time.RFC3339)
 	}
 	if until != "" {
-		u, err := ParseDate(until)
+		untilTime, err := ParseDate(until)
 		if err != nil {
 			return "", "", err
 		}
-		untilTime := u.Add(23*time.Hour + 59*time.Minute)
+		if !isTimestamp(until) {
+			untilTime = NextDay(untilTime)
+		}
 		until = untilTime.Format(time.RFC3339)
 	}
 	return since, until, nil
 }
+
+// NextDay returns midnight of the day after t in t's location
+func NextDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSinceUntil(t *testing.T) {
+	tests := []struct {
+		name      string
+		tz        string
+		since     string
+		until     string
+		wantSince string
+		wantUntil string
+	}{
+		{
+			name:      "single day",
+			tz:        "Asia/Tokyo",
+			since:     "2025-06-01",
+			until:     "2025-06-01",
+			wantSince: "2025-06-01T00:00:00+09:00",
+			wantUntil: "2025-06-02T00:00:00+09:00",
+		},
+		{
+			name:      "spring forward (23 hour day)",
+			tz:        "Europe/Berlin",
+			since:     "2025-03-30",
+			until:     "2025-03-30",
+			wantSince: "2025-03-30T00:00:00+01:00",
+			wantUntil: "2025-03-31T00:00:00+02:00",
+		},
+		{
+			name:      "fall back (25 hour day)",
+			tz:        "Europe/Berlin",
+			since:     "2025-10-26",
+			until:     "2025-10-26",
+			wantSince: "2025-10-26T00:00:00+02:00",
+			wantUntil: "2025-10-27T00:00:00+01:00",
+		},
+		{
+			name:      "range across DST transition",
+			tz:        "America/Los_Angeles",
+			since:     "2025-03-08",
+			until:     "2025-03-10",
+			wantSince: "2025-03-08T00:00:00-08:00",
+			wantUntil: "2025-03-11T00:00:00-07:00",
+		},
+		{
+			name:      "exact until timestamp",
+			tz:        "Europe/Berlin",
+			since:     "2025-03-30",
+			until:     "2025-03-30T12:30:00+02:00",
+			wantSince: "2025-03-30T00:00:00+01:00",
+			wantUntil: "2025-03-30T12:30:00+02:00",
+		},
+		{
+			name:      "since only",
+			tz:        "America/New_York",
+			since:     "2025-11-02",
+			wantSince: "2025-11-02T00:00:00-04:00",
+			wantUntil: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetTimeZone(tt.tz); err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = SetTimeZone("") }()
+
+			since, until, err := ParseSinceUntil(tt.since, tt.until)
+			if err != nil {
+				t.Fatalf("ParseSinceUntil() error = %v", err)
+			}
+			if since != tt.wantSince {
+				t.Errorf("since = %v, want %v", since, tt.wantSince)
+			}
+			if until != tt.wantUntil {
+				t.Errorf("until = %v, want %v", until, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestParseSinceUntilDefaultsToToday(t *testing.T) {
+	if err := SetTimeZone("Europe/Berlin"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetTimeZone("") }()
+	loc := GetLocation()
+	now = func() time.Time { return time.Date(2025, 3, 30, 23, 30, 0, 0, loc) }
+	defer func() { now = time.Now }()
+
+	since, until, err := ParseSinceUntil("", "")
+	if err != nil {
+		t.Fatalf("ParseSinceUntil() error = %v", err)
+	}
+	if since != "2025-03-30T00:00:00+01:00" {
+		t.Errorf("since = %v", since)
+	}
+	if until != "2025-03-31T00:00:00+02:00" {
+		t.Errorf("until = %v", until)
+	}
+}
+
+func TestParseDateRelative(t *testing.T) {
+	if err := SetTimeZone("Asia/Tokyo"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetTimeZone("") }()
+	loc := GetLocation()
+	// Wednesday
+	now = func() time.Time { return time.Date(2025, 6, 11, 15, 0, 0, 0, loc) }
+	defer func() { now = time.Now }()
+
+	tests := map[string]string{
+		"today":        "2025-06-11",
+		"tomorrow":     "2025-06-12",
+		"yesterday":    "2025-06-10",
+		"+3d":          "2025-06-14",
+		"-1w":          "2025-06-04",
+		"+1m":          "2025-07-11",
+		"monday":       "2025-06-16",
+		"wednesday":    "2025-06-11",
+		"next wed":     "2025-06-18",
+		"last friday":  "2025-06-06",
+		"this week":    "2025-06-09",
+		"next week":    "2025-06-16",
+		"this month":   "2025-06-01",
+		"last quarter": "2025-01-01",
+		"eom":          "2025-06-30",
+		"eoq":          "2025-06-30",
+		"bow":          "2025-06-09",
+	}
+	for expr, want := range tests {
+		t.Run(expr, func(t *testing.T) {
+			got, err := ParseDate(expr)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error = %v", expr, err)
+			}
+			if got.Format("2006-01-02") != want {
+				t.Errorf("ParseDate(%q) = %v, want %v", expr, got.Format("2006-01-02"), want)
+			}
+		})
+	}
+}
+
+func TestParseDateMonthEnd(t *testing.T) {
+	if err := SetTimeZone("Asia/Tokyo"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetTimeZone("") }()
+	loc := GetLocation()
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		today string
+		expr  string
+		want  string
+	}{
+		{"2025-01-31", "+1m", "2025-02-28"},
+		{"2024-01-31", "+1m", "2024-02-29"},
+		{"2025-03-31", "-1m", "2025-02-28"},
+		{"2025-05-31", "+1m", "2025-06-30"},
+		{"2025-01-31", "+13m", "2026-02-28"},
+		{"2024-02-29", "+1y", "2025-02-28"},
+		{"2024-02-29", "-4y", "2020-02-29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.today+" "+tt.expr, func(t *testing.T) {
+			today, err := time.ParseInLocation("2006-01-02", tt.today, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			now = func() time.Time { return today.Add(12 * time.Hour) }
+			got, err := ParseDate(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error = %v", tt.expr, err)
+			}
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.expr, got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandRange(t *testing.T) {
+	if err := SetTimeZone("Asia/Tokyo"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetTimeZone("") }()
+
+	tests := []struct {
+		name      string
+		base      string
+		wantSince string
+		wantUntil string
+	}{
+		{"week", "2025-06-11", "2025-06-09", "2025-06-15"},
+		{"month", "2025-02-11", "2025-02-01", "2025-02-28"},
+		{"quarter", "2025-08-20", "2025-07-01", "2025-09-30"},
+		{"year", "2024-02-29", "2024-01-01", "2024-12-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since, until, err := ExpandRange(tt.name, tt.base)
+			if err != nil {
+				t.Fatalf("ExpandRange() error = %v", err)
+			}
+			if since != tt.wantSince || until != tt.wantUntil {
+				t.Errorf("ExpandRange() = %v, %v, want %v, %v", since, until, tt.wantSince, tt.wantUntil)
+			}
+		})
+	}
+	if _, _, err := ExpandRange("fortnight", ""); err == nil {
+		t.Error("ExpandRange(fortnight) should fail")
+	}
+}
@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// now is replaceable for tests
+var now = time.Now
+
+var relativeOffsetPattern = regexp.MustCompile(`^([+-])(\d+)([dwmy])$`)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"sun":       time.Sunday,
+	"monday":    time.Monday,
+	"mon":       time.Monday,
+	"tuesday":   time.Tuesday,
+	"tue":       time.Tuesday,
+	"wednesday": time.Wednesday,
+	"wed":       time.Wednesday,
+	"thursday":  time.Thursday,
+	"thu":       time.Thursday,
+	"friday":    time.Friday,
+	"fri":       time.Friday,
+	"saturday":  time.Saturday,
+	"sat":       time.Saturday,
+}
+
+// Today returns midnight of the current day in loc
+func Today(loc *time.Location) time.Time {
+	return truncateDay(now().In(loc))
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// periodStart returns the first day of the week (Monday), month, quarter or year that contains t
+func periodStart(t time.Time, period string) (time.Time, error) {
+	t = truncateDay(t)
+	switch period {
+	case "day":
+		return t, nil
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		return t.AddDate(0, 0, -offset), nil
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+	case "quarter":
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location()), nil
+	case "year":
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown period: %s", period)
+}
+
+// addPeriods moves t by n weeks, months, quarters or years using calendar arithmetic
+func addPeriods(t time.Time, period string, n int) time.Time {
+	switch period {
+	case "day":
+		return t.AddDate(0, 0, n)
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return addMonths(t, n)
+	case "quarter":
+		return addMonths(t, 3*n)
+	case "year":
+		return addMonths(t, 12*n)
+	}
+	return t
+}
+
+// addMonths moves t by n months, clamping the day to the end of the month (Jan 31 +1m is Feb 28 or 29)
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(t.Day(), lastDay), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// periodEnd returns the last day of the period that contains t
+func periodEnd(t time.Time, period string) (time.Time, error) {
+	start, err := periodStart(t, period)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return addPeriods(start, period, 1).AddDate(0, 0, -1), nil
+}
+
+var periodAbbreviations = map[string]string{
+	"w": "week",
+	"m": "month",
+	"q": "quarter",
+	"y": "year",
+}
+
+// parseRelativeDate parses relative date expressions such as today, +3d, monday, next week or eom.
+// It returns midnight of the resulting day in loc.
+func parseRelativeDate(s string, loc *time.Location) (time.Time, bool) {
+	expr := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	today := Today(loc)
+
+	switch expr {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+
+	if m := relativeOffsetPattern.FindStringSubmatch(expr); m != nil {
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			return time.Time{}, false
+		}
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "d":
+			return today.AddDate(0, 0, n), true
+		case "w":
+			return today.AddDate(0, 0, 7*n), true
+		case "m":
+			return addMonths(today, n), true
+		case "y":
+			return addMonths(today, 12*n), true
+		}
+	}
+
+	// bow/eow, bom/eom, boq/eoq, boy/eoy
+	if len(expr) == 3 && (expr[:2] == "bo" || expr[:2] == "eo") {
+		if period, ok := periodAbbreviations[expr[2:]]; ok {
+			var t time.Time
+			var err error
+			if expr[:2] == "bo" {
+				t, err = periodStart(today, period)
+			} else {
+				t, err = periodEnd(today, period)
+			}
+			return t, err == nil
+		}
+	}
+
+	// monday, next monday, last monday
+	words := strings.Split(expr, " ")
+	if wd, ok := weekdays[words[len(words)-1]]; ok && len(words) <= 2 {
+		diff := (int(wd) - int(today.Weekday()) + 7) % 7
+		if len(words) == 1 {
+			return today.AddDate(0, 0, diff), true
+		}
+		switch words[0] {
+		case "this":
+			return today.AddDate(0, 0, diff), true
+		case "next":
+			if diff == 0 {
+				diff = 7
+			}
+			return today.AddDate(0, 0, diff), true
+		case "last":
+			back := (int(today.Weekday()) - int(wd) + 7) % 7
+			if back == 0 {
+				back = 7
+			}
+			return today.AddDate(0, 0, -back), true
+		}
+		return time.Time{}, false
+	}
+
+	// this week, next month, last quarter ...
+	if len(words) == 2 {
+		start, err := periodStart(today, words[1])
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch words[0] {
+		case "this":
+			return start, true
+		case "next":
+			return addPeriods(start, words[1], 1), true
+		case "last":
+			return addPeriods(start, words[1], -1), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ExpandRange converts a --range shortcut (day, week, month, quarter or year) to since and until dates (YYYY-MM-DD).
+// The range contains base, which may be any date expression accepted by ParseDate (today if empty).
+func ExpandRange(name, base string) (string, string, error) {
+	loc := GetLocation()
+	t := Today(loc)
+	if base != "" {
+		var err error
+		t, err = ParseDate(base)
+		if err != nil {
+			return "", "", err
+		}
+		t = t.In(loc)
+	}
+	name = strings.ToLower(name)
+	start, err := periodStart(t, name)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid range: %s (must be day, week, month, quarter or year)", name)
+	}
+	end, err := periodEnd(t, name)
+	if err != nil {
+		return "", "", err
+	}
+	return start.Format("2006-01-02"), end.Format("2006-01-02"), nil
+}
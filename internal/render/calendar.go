@@ -1,6 +1,8 @@
 package render
 
 import (
+	"maps"
+	"slices"
 	"strings"
 
 	"google.golang.org/api/calendar/v3"
@@ -19,10 +21,24 @@ func NewCalendarListFieldGetters() *CalendarListFieldGetters {
 			"SUMMARY":     func(e *calendar.CalendarListEntry) string { return e.Summary },
 			"DESCRIPTION": func(e *calendar.CalendarListEntry) string { return e.Description },
 			"LOCATION":    func(e *calendar.CalendarListEntry) string { return e.Location },
+			"TIME_ZONE":   func(e *calendar.CalendarListEntry) string { return e.TimeZone },
+			"ACCESS_ROLE": func(e *calendar.CalendarListEntry) string { return e.AccessRole },
+			"PRIMARY":     func(e *calendar.CalendarListEntry) string { return toString(e.Primary) },
+			"SELECTED":    func(e *calendar.CalendarListEntry) string { return toString(e.Selected) },
+			"COLOR_ID":    func(e *calendar.CalendarListEntry) string { return e.ColorId },
 		},
 	}
 }
 
+func (g *CalendarListFieldGetters) Names() []string {
+	return slices.Sorted(maps.Keys(g.Func))
+}
+
+func (g *CalendarListFieldGetters) HasField(field string) bool {
+	_, ok := g.Func[strings.ToUpper(field)]
+	return ok
+}
+
 func (g *CalendarListFieldGetters) GetField(e *calendar.CalendarListEntry, field string) string {
 	field = strings.ToUpper(field)
 	if getter, ok := g.Func[field]; ok {
@@ -32,22 +48,22 @@ func (g *CalendarListFieldGetters) GetField(e *calendar.CalendarListEntry, field
 }
 
 func (r *Renderer) RenderCalendarList(cl *calendar.CalendarList, header []string) {
-	if r.exporter != nil {
-		r.exporter.Export(cl)
+	if r.exportData(cl) {
 		return
 	}
 	getter := NewCalendarListFieldGetters()
-	table := r.newTableWriter(header)
+	validateColumns(header, getter)
+	rows := make([][]string, 0, len(cl.Items))
 	for _, entry := range cl.Items {
 		row := make([]string, len(header))
 		for i, h := range header {
 			row[i] = getter.GetField(entry, h)
 		}
-		table.Append(row)
+		rows = append(rows, row)
 	}
-	table.Render()
+	r.renderTable(header, rows)
 }
 
 func (r *Renderer) RenderCalendarListDefault(cl *calendar.CalendarList) {
-	r.RenderCalendarList(cl, []string{"Id", "Summary"})
+	r.RenderCalendarList(cl, r.columnsOr("Id", "Summary"))
 }
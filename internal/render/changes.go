@@ -0,0 +1,67 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"google.golang.org/api/calendar/v3"
+)
+
+// changeFields are the event fields compared by RenderEventChanges
+var changeFields = []string{"SUMMARY", "START", "END", "LOCATION", "DESCRIPTION", "VISIBILITY", "ATTENDEES", "RECURRENCE", "CONFERENCE"}
+
+// changeField returns the value of a field shown in a change diff
+func changeField(getter *EventFieldGetters, e *calendar.Event, field string) string {
+	if e == nil {
+		return ""
+	}
+	if field == "ATTENDEES" {
+		emails := make([]string, 0, len(e.Attendees))
+		for _, a := range e.Attendees {
+			emails = append(emails, a.Email)
+		}
+		return strings.Join(emails, ", ")
+	}
+	return getter.GetField(e, field)
+}
+
+// RenderEventChanges renders the field diff of the planned changes (e.g. for --dry-run)
+func (r *Renderer) RenderEventChanges(changes []*gcalendar.EventChange) {
+	if r.exportData(changes) {
+		return
+	}
+	getter := NewEventFieldGetters()
+	rows := [][]string{}
+	for _, c := range changes {
+		id := "(new)"
+		if c.Before != nil {
+			id = c.Before.Id
+		}
+		switch c.Action {
+		case gcalendar.ChangeMove:
+			rows = append(rows, []string{string(c.Action), id, "CALENDAR", c.CalendarID, c.Destination})
+		case gcalendar.ChangeInsert:
+			rows = append(rows, []string{string(c.Action), id, "CALENDAR", "", c.CalendarID})
+		}
+		for _, field := range changeFields {
+			var before, after string
+			if c.Action != gcalendar.ChangeInsert {
+				before = changeField(getter, c.Before, field)
+			}
+			switch c.Action {
+			case gcalendar.ChangeMove:
+				after = before
+			case gcalendar.ChangeInsert, gcalendar.ChangeUpdate:
+				after = changeField(getter, c.After, field)
+			}
+			if before == after {
+				continue
+			}
+			rows = append(rows, []string{string(c.Action), id, field, before, after})
+		}
+	}
+	r.renderTable([]string{"ACTION", "ID", "FIELD", "BEFORE", "AFTER"}, rows, func(table *tablewriter.Table) {
+		table.SetAutoWrapText(false)
+	})
+}
@@ -6,6 +6,9 @@ import (
 )
 
 func (r *Renderer) decorate(event *calendar.Event, text string) string {
+	if r.exporter != nil {
+		return text
+	}
 	if gcalendar.GetSelfResponseStatus(event) == "declined" {
 		return "\x1b[9m" + text + "\x1b[0m"
 	}
@@ -1,11 +1,15 @@
 package render
 
 import (
+	"maps"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
 	"google.golang.org/api/calendar/v3"
 )
 
@@ -24,7 +28,7 @@ func getPeriod(e *calendar.Event) string {
 	parseTime := func(dt string) string {
 		t, err := time.Parse(time.RFC3339, dt)
 		if err == nil {
-			return t.Format("15:04")
+			return t.In(parser.GetLocation()).Format("15:04")
 		}
 		if len(dt) >= 16 && dt[10] == 'T' {
 			return dt[11:16]
@@ -38,11 +42,20 @@ func getPeriod(e *calendar.Event) string {
 
 func getDate(e *calendar.Event) string {
 	if e.Start.DateTime != "" {
-		return e.Start.DateTime[:10] // YYYY-MM-DD
+		return localDateTime(e.Start.DateTime)[:10] // YYYY-MM-DD
 	}
 	return e.Start.Date
 }
 
+// localDateTime converts a RFC3339 date time to the display timezone
+func localDateTime(dt string) string {
+	t, err := time.Parse(time.RFC3339, dt)
+	if err != nil {
+		return dt
+	}
+	return t.In(parser.GetLocation()).Format(time.RFC3339)
+}
+
 func getDateTime(e *calendar.Event) string {
 	period := getPeriod(e)
 	date := getDate(e)
@@ -58,13 +71,13 @@ func NewEventFieldGetters() *EventFieldGetters {
 			"ID": func(e *calendar.Event) string { return e.Id },
 			"START": func(e *calendar.Event) string {
 				if e.Start.DateTime != "" {
-					return e.Start.DateTime
+					return localDateTime(e.Start.DateTime)
 				}
 				return e.Start.Date
 			},
 			"END": func(e *calendar.Event) string {
 				if e.End.DateTime != "" {
-					return e.End.DateTime
+					return localDateTime(e.End.DateTime)
 				}
 				return e.End.Date
 			},
@@ -88,10 +101,52 @@ func NewEventFieldGetters() *EventFieldGetters {
 			},
 			"DESCRIPTION": func(e *calendar.Event) string { return e.Description },
 			"LOCATION":    func(e *calendar.Event) string { return e.Location },
+			"ORGANIZER": func(e *calendar.Event) string {
+				if e.Organizer == nil {
+					return ""
+				}
+				if e.Organizer.DisplayName != "" {
+					return e.Organizer.DisplayName
+				}
+				return e.Organizer.Email
+			},
+			"ATTENDEES": func(e *calendar.Event) string {
+				return strconv.Itoa(len(e.Attendees))
+			},
+			"RESPONSE_STATUS": func(e *calendar.Event) string {
+				return gcalendar.GetSelfResponseStatus(e)
+			},
+			"CONFERENCE": func(e *calendar.Event) string {
+				return gcalendar.GetConferenceLink(e)
+			},
+			// CALENDAR is filled from Renderer.EventCalendars
+			"CALENDAR":   func(e *calendar.Event) string { return "" },
+			"VISIBILITY": func(e *calendar.Event) string { return e.Visibility },
+			"STATUS":     func(e *calendar.Event) string { return e.Status },
+			"DURATION": func(e *calendar.Event) string {
+				start, end, err := gcalendar.GetEventTimeRange(e)
+				if err != nil {
+					return ""
+				}
+				return formatDuration(end.Sub(start))
+			},
+			"COLOR_ID": func(e *calendar.Event) string { return e.ColorId },
+			"RECURRENCE": func(e *calendar.Event) string {
+				return strings.Join(e.Recurrence, " ")
+			},
 		},
 	}
 }
 
+func (g *EventFieldGetters) Names() []string {
+	return slices.Sorted(maps.Keys(g.Func))
+}
+
+func (g *EventFieldGetters) HasField(field string) bool {
+	_, ok := g.Func[strings.ToUpper(field)]
+	return ok
+}
+
 func (g *EventFieldGetters) GetField(e *calendar.Event, field string) string {
 	field = strings.ToUpper(field)
 	if getter, ok := g.Func[field]; ok {
@@ -101,35 +156,40 @@ func (g *EventFieldGetters) GetField(e *calendar.Event, field string) string {
 }
 
 func (r *Renderer) RenderEvents(events *calendar.Events, headers []string) {
-	if r.exporter != nil {
-		r.exporter.Export(events)
+	if r.exportData(events) {
 		return
 	}
-	if r.Debug {
+	if r.Debug || r.ShowID {
 		if !slices.Contains(headers, "ID") {
 			headers = append([]string{"ID"}, headers...)
 		}
 	}
 	getter := NewEventFieldGetters()
-	table := r.newTableWriter(headers)
-	table.SetAutoWrapText(false)
+	getter.Func["CALENDAR"] = func(e *calendar.Event) string { return r.EventCalendars[e.Id] }
+	validateColumns(headers, getter)
+	rows := make([][]string, 0, len(events.Items))
 	for _, event := range events.Items {
-		if !r.ShowDeclined {
-			if gcalendar.GetSelfResponseStatus(event) == "declined" {
-				continue
-			}
-		}
 		row := make([]string, len(headers))
 		for i, header := range headers {
 			row[i] = getter.GetField(event, header)
 			row[i] = r.decorate(event, row[i])
 		}
-		table.Append(row)
+		rows = append(rows, row)
 	}
-	table.Render()
+	r.renderTable(headers, rows, func(table *tablewriter.Table) {
+		table.SetAutoWrapText(false)
+	})
 }
 
 // 既存のRenderEventsはデフォルトヘッダーで呼び出す
 func (r *Renderer) RenderEventsDefault(events *calendar.Events) {
-	r.RenderEvents(events, []string{"DATE_TIME", "SUMMARY"})
+	r.RenderEvents(events, r.columnsOr("DATE_TIME", "SUMMARY"))
+}
+
+// RenderEvent renders a single event (e.g. the result of create or edit)
+func (r *Renderer) RenderEvent(event *calendar.Event) {
+	if r.exportData(event) {
+		return
+	}
+	r.RenderEvents(&calendar.Events{Items: []*calendar.Event{event}}, r.columnsOr("ID", "DATE_TIME", "SUMMARY", "CONFERENCE"))
 }
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/srz-zumix/gali/internal/gcalendar"
+)
+
+type TimeSlotFieldGetter func(s gcalendar.TimeSlot) string
+
+type TimeSlotFieldGetters struct {
+	Func map[string]TimeSlotFieldGetter
+}
+
+func NewTimeSlotFieldGetters() *TimeSlotFieldGetters {
+	return &TimeSlotFieldGetters{
+		Func: map[string]TimeSlotFieldGetter{
+			"START": func(s gcalendar.TimeSlot) string { return s.Start.Format(time.RFC3339) },
+			"END":   func(s gcalendar.TimeSlot) string { return s.End.Format(time.RFC3339) },
+			"DATE":  func(s gcalendar.TimeSlot) string { return s.Start.Format("2006-01-02 (Mon)") },
+			"TIME": func(s gcalendar.TimeSlot) string {
+				return s.Start.Format("15:04") + "-" + s.End.Format("15:04")
+			},
+			"DURATION": func(s gcalendar.TimeSlot) string { return formatDuration(s.Duration()) },
+		},
+	}
+}
+
+func (g *TimeSlotFieldGetters) Names() []string {
+	return slices.Sorted(maps.Keys(g.Func))
+}
+
+func (g *TimeSlotFieldGetters) HasField(field string) bool {
+	_, ok := g.Func[strings.ToUpper(field)]
+	return ok
+}
+
+func (g *TimeSlotFieldGetters) GetField(s gcalendar.TimeSlot, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := g.Func[field]; ok {
+		return getter(s)
+	}
+	return ""
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d / time.Hour)
+	m := int((d % time.Hour) / time.Minute)
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	}
+}
+
+func (r *Renderer) RenderTimeSlots(slots []gcalendar.TimeSlot, headers []string) {
+	if r.exportData(slots) {
+		return
+	}
+	getter := NewTimeSlotFieldGetters()
+	validateColumns(headers, getter)
+	rows := make([][]string, 0, len(slots))
+	for _, slot := range slots {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(slot, header)
+		}
+		rows = append(rows, row)
+	}
+	r.renderTable(headers, rows, func(table *tablewriter.Table) {
+		table.SetAutoWrapText(false)
+	})
+}
+
+func (r *Renderer) RenderTimeSlotsDefault(slots []gcalendar.TimeSlot) {
+	r.RenderTimeSlots(slots, r.columnsOr("DATE", "TIME", "DURATION"))
+}
@@ -0,0 +1,289 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/srz-zumix/gali/version"
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	icsLocalTimeFormat = "20060102T150405"
+	icsUTCTimeFormat   = "20060102T150405Z"
+	icsLineLimit       = 75
+)
+
+// ICSExporter writes events as an RFC 5545 VCALENDAR
+type ICSExporter struct{}
+
+func NewICSExporter() *ICSExporter {
+	return &ICSExporter{}
+}
+
+func (e *ICSExporter) Export(data any) {
+	e.ExportTo(os.Stdout, data)
+}
+
+// ExportTo writes the events to w (the output of the renderer)
+func (e *ICSExporter) ExportTo(w io.Writer, data any) {
+	events, ok := data.(*calendar.Events)
+	if !ok {
+		log.Fatalf("iCalendar output is not supported for %T", data)
+	}
+	if err := WriteICS(w, events); err != nil {
+		log.Fatalf("Failed to write iCalendar: %v", err)
+	}
+}
+
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a content line folded at 75 octets without splitting UTF-8 sequences
+func (w *icsWriter) line(s string) {
+	if w.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := icsLineLimit
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > limit {
+			b.WriteString("\r\n ")
+			n = 0
+			limit = icsLineLimit - 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	_, w.err = io.WriteString(w.w, b.String())
+}
+
+func (w *icsWriter) property(name, value string) {
+	if value == "" {
+		return
+	}
+	w.line(name + ":" + value)
+}
+
+func icsEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+func icsParamValue(s string) string {
+	if strings.ContainsAny(s, ";:,") {
+		return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+	}
+	return s
+}
+
+// icsDateTime returns the property parameters and value of an event date time
+func icsDateTime(dt *calendar.EventDateTime, defaultTZ string) (string, string, *time.Location, time.Time) {
+	if dt.DateTime == "" {
+		return ";VALUE=DATE", strings.ReplaceAll(dt.Date, "-", ""), nil, time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, dt.DateTime)
+	if err != nil {
+		return "", "", nil, time.Time{}
+	}
+	tz := dt.TimeZone
+	if tz == "" {
+		tz = defaultTZ
+	}
+	if tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return ";TZID=" + tz, t.In(loc).Format(icsLocalTimeFormat), loc, t
+		}
+	}
+	return "", t.UTC().Format(icsUTCTimeFormat), nil, t
+}
+
+func icsTimestamp(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t = time.Now()
+	}
+	return t.UTC().Format(icsUTCTimeFormat)
+}
+
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, (seconds%3600)/60)
+}
+
+// writeVTimezone writes the observances of loc that are in effect between first and last
+func (w *icsWriter) writeVTimezone(loc *time.Location, first, last time.Time) {
+	w.line("BEGIN:VTIMEZONE")
+	w.property("TZID", loc.String())
+	t := first.In(loc)
+	for {
+		start, end := t.ZoneBounds()
+		name, offset := t.Zone()
+		prevOffset := offset
+		if !start.IsZero() {
+			_, prevOffset = start.Add(-time.Second).In(loc).Zone()
+		} else {
+			start = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+		kind := "STANDARD"
+		if t.IsDST() {
+			kind = "DAYLIGHT"
+		}
+		w.line("BEGIN:" + kind)
+		w.property("DTSTART", start.Add(time.Duration(prevOffset)*time.Second).UTC().Format(icsLocalTimeFormat))
+		w.property("TZOFFSETFROM", icsOffset(prevOffset))
+		w.property("TZOFFSETTO", icsOffset(offset))
+		w.property("TZNAME", icsEscape(name))
+		w.line("END:" + kind)
+		if end.IsZero() || end.After(last) {
+			break
+		}
+		t = end.In(loc)
+	}
+	w.line("END:VTIMEZONE")
+}
+
+func (w *icsWriter) writeVEvent(event *calendar.Event, defaultTZ string) {
+	w.line("BEGIN:VEVENT")
+	uid := event.ICalUID
+	if uid == "" || event.OriginalStartTime != nil {
+		// Expanded instances are independent events, which need their own UID
+		uid = event.Id
+	}
+	w.property("UID", icsEscape(uid))
+	w.property("DTSTAMP", icsTimestamp(event.Updated))
+	if event.Start != nil {
+		params, value, _, _ := icsDateTime(event.Start, defaultTZ)
+		w.property("DTSTART"+params, value)
+	}
+	if event.End != nil && !event.EndTimeUnspecified {
+		params, value, _, _ := icsDateTime(event.End, defaultTZ)
+		w.property("DTEND"+params, value)
+	}
+	if event.OriginalStartTime == nil {
+		for _, rule := range event.Recurrence {
+			w.line(rule)
+		}
+	}
+	summary := event.Summary
+	if summary == "" {
+		summary = "Private Event"
+	}
+	w.property("SUMMARY", icsEscape(summary))
+	w.property("LOCATION", icsEscape(event.Location))
+	w.property("DESCRIPTION", icsEscape(event.Description))
+	w.property("URL", event.HtmlLink)
+	w.property("STATUS", strings.ToUpper(event.Status))
+	if event.Visibility == "private" || event.Visibility == "confidential" {
+		w.property("CLASS", strings.ToUpper(event.Visibility))
+	}
+	if event.Transparency == "transparent" {
+		w.property("TRANSP", "TRANSPARENT")
+	}
+	if event.Sequence > 0 {
+		w.property("SEQUENCE", fmt.Sprintf("%d", event.Sequence))
+	}
+	if event.Organizer != nil && event.Organizer.Email != "" {
+		params := ""
+		if event.Organizer.DisplayName != "" {
+			params = ";CN=" + icsParamValue(event.Organizer.DisplayName)
+		}
+		w.property("ORGANIZER"+params, "mailto:"+event.Organizer.Email)
+	}
+	for _, attendee := range event.Attendees {
+		if attendee.Email == "" {
+			continue
+		}
+		params := ""
+		if attendee.DisplayName != "" {
+			params += ";CN=" + icsParamValue(attendee.DisplayName)
+		}
+		if attendee.Resource {
+			params += ";CUTYPE=ROOM"
+		}
+		if attendee.Optional {
+			params += ";ROLE=OPT-PARTICIPANT"
+		} else {
+			params += ";ROLE=REQ-PARTICIPANT"
+		}
+		switch attendee.ResponseStatus {
+		case "accepted":
+			params += ";PARTSTAT=ACCEPTED"
+		case "declined":
+			params += ";PARTSTAT=DECLINED"
+		case "tentative":
+			params += ";PARTSTAT=TENTATIVE"
+		case "needsAction":
+			params += ";PARTSTAT=NEEDS-ACTION"
+		}
+		w.property("ATTENDEE"+params, "mailto:"+attendee.Email)
+	}
+	w.line("END:VEVENT")
+}
+
+// WriteICS writes events as an RFC 5545 VCALENDAR
+// Expanded instances of recurring events are written as independent events without RECURRENCE-ID,
+// because importers reject overrides whose master event is not in the file.
+func WriteICS(out io.Writer, events *calendar.Events) error {
+	w := &icsWriter{w: out}
+	w.line("BEGIN:VCALENDAR")
+	w.property("VERSION", "2.0")
+	w.property("PRODID", "-//srz-zumix//gali "+version.Version+"//EN")
+	w.property("CALSCALE", "GREGORIAN")
+	w.property("X-WR-CALNAME", icsEscape(events.Summary))
+	w.property("X-WR-TIMEZONE", events.TimeZone)
+
+	// Collect the time range of each timezone referenced by TZID
+	type zoneRange struct {
+		loc         *time.Location
+		first, last time.Time
+	}
+	zones := map[string]*zoneRange{}
+	for _, event := range events.Items {
+		for _, dt := range []*calendar.EventDateTime{event.Start, event.End} {
+			if dt == nil {
+				continue
+			}
+			_, _, loc, t := icsDateTime(dt, events.TimeZone)
+			if loc == nil {
+				continue
+			}
+			z, ok := zones[loc.String()]
+			if !ok {
+				zones[loc.String()] = &zoneRange{loc: loc, first: t, last: t}
+				continue
+			}
+			if t.Before(z.first) {
+				z.first = t
+			}
+			if t.After(z.last) {
+				z.last = t
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(zones)) {
+		z := zones[name]
+		w.writeVTimezone(z.loc, z.first, z.last)
+	}
+
+	for _, event := range events.Items {
+		w.writeVEvent(event, events.TimeZone)
+	}
+	w.line("END:VCALENDAR")
+	return w.err
+}
@@ -0,0 +1,133 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestICSLineFolding(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		lines int
+	}{
+		{"short line", "SUMMARY:Weekly sync", 1},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67), 1},
+		{"76 octets", "SUMMARY:" + strings.Repeat("a", 68), 2},
+		{"long ASCII line", "DESCRIPTION:" + strings.Repeat("0123456789", 30), 5},
+		{"multi-byte characters", "SUMMARY:" + strings.Repeat("会議", 40), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := &icsWriter{w: &buf}
+			w.line(tt.value)
+			if w.err != nil {
+				t.Fatal(w.err)
+			}
+			out := buf.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line does not end with CRLF: %q", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.lines {
+				t.Errorf("got %d lines, want %d: %q", len(lines), tt.lines, lines)
+			}
+			var unfolded strings.Builder
+			for i, line := range lines {
+				if len(line) > icsLineLimit {
+					t.Errorf("line %d has %d octets: %q", i, len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(line, " ") {
+						t.Errorf("continuation line %d does not start with a space: %q", i, line)
+					}
+					line = line[1:]
+				}
+				unfolded.WriteString(line)
+			}
+			if unfolded.String() != tt.value {
+				t.Errorf("unfolded = %q, want %q", unfolded.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestICSEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{`C:\path`, `C:\\path`},
+		{"a;b,c", `a\;b\,c`},
+		{"line1\nline2", `line1\nline2`},
+		{"line1\r\nline2", `line1\nline2`},
+		{"colon: kept", "colon: kept"},
+		{`\;`, `\\\;`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := icsEscape(tt.in); got != tt.want {
+				t.Errorf("icsEscape(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteICSExpandedInstance(t *testing.T) {
+	events := &calendar.Events{
+		Items: []*calendar.Event{
+			{
+				Id:                "abc_20250610T010000Z",
+				ICalUID:           "abc@google.com",
+				RecurringEventId:  "abc",
+				Summary:           "Standup",
+				Start:             &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00+09:00"},
+				End:               &calendar.EventDateTime{DateTime: "2025-06-10T10:15:00+09:00"},
+				OriginalStartTime: &calendar.EventDateTime{DateTime: "2025-06-10T10:00:00+09:00"},
+				Recurrence:        []string{"RRULE:FREQ=DAILY"},
+			},
+			{
+				Id:                "abc_20250611T010000Z",
+				ICalUID:           "abc@google.com",
+				RecurringEventId:  "abc",
+				Summary:           "Standup",
+				Start:             &calendar.EventDateTime{DateTime: "2025-06-11T10:00:00+09:00"},
+				End:               &calendar.EventDateTime{DateTime: "2025-06-11T10:15:00+09:00"},
+				OriginalStartTime: &calendar.EventDateTime{DateTime: "2025-06-11T10:00:00+09:00"},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, events); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, unwanted := range []string{"RECURRENCE-ID", "RRULE"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %s:\n%s", unwanted, out)
+		}
+	}
+	for _, uid := range []string{"UID:abc_20250610T010000Z\r\n", "UID:abc_20250611T010000Z\r\n"} {
+		if !strings.Contains(out, uid) {
+			t.Errorf("output does not contain %q:\n%s", uid, out)
+		}
+	}
+}
+
+func TestICSExporterWritesToRendererOutput(t *testing.T) {
+	r := NewStringRenderer()
+	r.Renderer.SetExporter(NewICSExporter())
+	r.Renderer.RenderEventsDefault(&calendar.Events{})
+	if !strings.HasPrefix(r.Stdout.String(), "BEGIN:VCALENDAR\r\n") {
+		t.Errorf("renderer output = %q", r.Stdout.String())
+	}
+}
@@ -1,21 +1,49 @@
 package render
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"os"
+
+	"github.com/cli/cli/pkg/export"
 )
 
-func OutputJSON(events any) {
+func OutputJSON(w io.Writer, events any) {
 	b, err := json.MarshalIndent(events, "", "  ")
 	if err != nil {
 		log.Fatalf("Failed to marshal events to JSON: %v", err)
 	}
-	fmt.Println(string(b))
+	fmt.Fprintln(w, string(b)) // nolint
 }
 
-type JSONExporter struct{}
+// OutputJQ filters the JSON representation of data with a jq expression
+func OutputJQ(w io.Writer, data any, expr string) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Fatalf("Failed to marshal events to JSON: %v", err)
+	}
+	if err := export.FilterJSON(w, bytes.NewReader(b), expr); err != nil {
+		log.Fatalf("Failed to apply jq expression: %v", err)
+	}
+}
+
+type JSONExporter struct {
+	// JQ is an optional jq expression to filter the output
+	JQ string
+}
 
 func (j *JSONExporter) Export(data any) {
-	OutputJSON(data)
+	j.ExportTo(os.Stdout, data)
+}
+
+// ExportTo writes the JSON (filtered by JQ) of data to w (the output of the renderer)
+func (j *JSONExporter) ExportTo(w io.Writer, data any) {
+	if j.JQ != "" {
+		OutputJQ(w, data, j.JQ)
+		return
+	}
+	OutputJSON(w, data)
 }
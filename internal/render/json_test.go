@@ -0,0 +1,31 @@
+package render
+
+import (
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestJSONExporterJQ(t *testing.T) {
+	exporter, err := NewExporter("", ExporterOptions{JQ: ".items[].summary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewStringRenderer()
+	r.Renderer.SetExporter(exporter)
+	r.Renderer.RenderEventsDefault(&calendar.Events{
+		Items: []*calendar.Event{{Summary: "Standup"}, {Summary: "Review"}},
+	})
+	if got, want := r.Stdout.String(), "Standup\nReview\n"; got != want {
+		t.Errorf("renderer output = %q, want %q", got, want)
+	}
+}
+
+func TestJSONExporterWritesToRendererOutput(t *testing.T) {
+	r := NewStringRenderer()
+	r.Renderer.SetExporter(&JSONExporter{})
+	r.Renderer.RenderEventsDefault(&calendar.Events{Summary: "team@example.com"})
+	if got, want := r.Stdout.String(), "{\n  \"summary\": \"team@example.com\"\n}\n"; got != want {
+		t.Errorf("renderer output = %q, want %q", got, want)
+	}
+}
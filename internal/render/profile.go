@@ -0,0 +1,62 @@
+package render
+
+import (
+	"strings"
+	"time"
+
+	"github.com/srz-zumix/gali/internal/gcalendar"
+	"github.com/srz-zumix/gali/internal/parser"
+)
+
+// ProfileInfo describes an account profile for gali auth list
+type ProfileInfo struct {
+	Name        string `json:"name"`
+	Current     bool   `json:"current"`
+	Credentials string `json:"credentials,omitempty"`
+	Calendar    string `json:"calendar,omitempty"`
+	TokenFile   string `json:"tokenFile"`
+	LoggedIn    bool   `json:"loggedIn"`
+}
+
+func (r *Renderer) RenderProfiles(profiles []*ProfileInfo) {
+	if r.exportData(profiles) {
+		return
+	}
+	rows := make([][]string, 0, len(profiles))
+	for _, p := range profiles {
+		current := ""
+		if p.Current {
+			current = "*"
+		}
+		loggedIn := "no"
+		if p.LoggedIn {
+			loggedIn = "yes"
+		}
+		rows = append(rows, []string{current, p.Name, p.Credentials, p.Calendar, p.TokenFile, loggedIn})
+	}
+	r.renderTable([]string{"CURRENT", "NAME", "CREDENTIALS", "CALENDAR", "TOKEN_FILE", "LOGGED_IN"}, rows)
+}
+
+// RenderAuthStatus renders the credentials used by the current profile
+func (r *Renderer) RenderAuthStatus(status *gcalendar.AuthStatus) {
+	if r.exportData(status) {
+		return
+	}
+	lines := [][2]string{
+		{"Profile", status.Profile},
+		{"Source", status.Source},
+		{"Credentials", status.Credentials},
+		{"Impersonate", status.Impersonate},
+		{"Token file", status.TokenFile},
+		{"Account", status.Email},
+		{"Scopes", strings.Join(status.Scopes, " ")},
+	}
+	if !status.Expiry.IsZero() {
+		lines = append(lines, [2]string{"Expiry", status.Expiry.In(parser.GetLocation()).Format(time.RFC3339)})
+	}
+	for _, line := range lines {
+		if line[1] != "" {
+			r.writeLine(line[0] + ": " + line[1])
+		}
+	}
+}
@@ -3,6 +3,8 @@ package render
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 
 	"github.com/cli/cli/pkg/iostreams"
@@ -13,12 +15,22 @@ type Exporter interface {
 	Export(any)
 }
 
+// WriterExporter is an exporter that writes to the output of the renderer
+type WriterExporter interface {
+	ExportTo(w io.Writer, data any)
+}
+
 type Renderer struct {
-	IO           *iostreams.IOStreams
-	exporter     Exporter
-	Color        bool
-	ShowDeclined bool
-	Debug        bool
+	IO       *iostreams.IOStreams
+	exporter Exporter
+	Color    bool
+	Debug    bool
+	// ShowID adds the event ID column (needed by edit, move and delete)
+	ShowID bool
+	// Columns overrides the default columns of the table view and table exporters
+	Columns []string
+	// EventCalendars maps event IDs to the calendar shown in the CALENDAR column
+	EventCalendars map[string]string
 }
 
 type StringRenderer struct {
@@ -41,9 +53,8 @@ var ColorFlags = []string{
 
 func NewRenderer() *Renderer {
 	return &Renderer{
-		IO:           iostreams.System(),
-		ShowDeclined: false,
-		Debug:        false,
+		IO:    iostreams.System(),
+		Debug: false,
 	}
 }
 
@@ -73,11 +84,68 @@ func GetExporter(name string) Exporter {
 	switch name {
 	case "json":
 		return &JSONExporter{}
+	case "csv":
+		return NewCSVExporter()
+	case "tsv":
+		return NewTSVExporter()
+	case "markdown", "md":
+		return &MarkdownExporter{}
+	case "ics":
+		return NewICSExporter()
 	default:
 		return nil
 	}
 }
 
+type ExporterOptions struct {
+	Template     string
+	TemplateFile string
+	JQ           string
+}
+
+// NewExporter returns the exporter for the output format, including formats that need options.
+// Conflicting options (e.g. --template with --format json) are rejected instead of ignored.
+func NewExporter(name string, opts ExporterOptions) (Exporter, error) {
+	hasTemplate := opts.Template != "" || opts.TemplateFile != ""
+	if opts.Template != "" && opts.TemplateFile != "" {
+		return nil, fmt.Errorf("--template and --template-file cannot be used together")
+	}
+	if hasTemplate && opts.JQ != "" {
+		return nil, fmt.Errorf("--template and --jq cannot be used together")
+	}
+	if name == "" && hasTemplate {
+		name = "template"
+	}
+	if name == "" && opts.JQ != "" {
+		name = "json"
+	}
+	if hasTemplate && name != "template" {
+		return nil, fmt.Errorf("--template is only supported with template format")
+	}
+	if opts.JQ != "" && name != "json" {
+		return nil, fmt.Errorf("--jq is only supported with json format")
+	}
+	switch name {
+	case "json":
+		return &JSONExporter{JQ: opts.JQ}, nil
+	case "template":
+		tpl := opts.Template
+		if opts.TemplateFile != "" {
+			b, err := os.ReadFile(opts.TemplateFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read template file: %w", err)
+			}
+			tpl = string(b)
+		}
+		if tpl == "" {
+			return nil, fmt.Errorf("--template or --template-file is required for template format")
+		}
+		return NewTemplateExporter(tpl, iostreams.System().ColorEnabled())
+	default:
+		return GetExporter(name), nil
+	}
+}
+
 func (r *Renderer) SetExporter(exporter Exporter) {
 	r.exporter = exporter
 }
@@ -0,0 +1,45 @@
+package render
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewExporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		opts    ExporterOptions
+		want    string
+		wantErr bool
+	}{
+		{"text", "", ExporterOptions{}, "<nil>", false},
+		{"json", "json", ExporterOptions{}, "*render.JSONExporter", false},
+		{"jq implies json", "", ExporterOptions{JQ: ".items"}, "*render.JSONExporter", false},
+		{"jq with json", "json", ExporterOptions{JQ: ".items"}, "*render.JSONExporter", false},
+		{"template implies template", "", ExporterOptions{Template: "{{.}}"}, "*render.TemplateExporter", false},
+		{"template format", "template", ExporterOptions{Template: "{{.}}"}, "*render.TemplateExporter", false},
+		{"csv", "csv", ExporterOptions{}, "*render.CSVExporter", false},
+		{"template without template", "template", ExporterOptions{}, "", true},
+		{"template and template-file", "", ExporterOptions{Template: "{{.}}", TemplateFile: "tpl"}, "", true},
+		{"template and jq", "", ExporterOptions{Template: "{{.}}", JQ: ".items"}, "", true},
+		{"template with json", "json", ExporterOptions{Template: "{{.}}"}, "", true},
+		{"jq with csv", "csv", ExporterOptions{JQ: ".items"}, "", true},
+		{"invalid template", "", ExporterOptions{Template: "{{"}, "", true},
+		{"missing template file", "", ExporterOptions{TemplateFile: "testdata/missing.tmpl"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := NewExporter(tt.format, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewExporter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := fmt.Sprintf("%T", exporter); got != tt.want {
+				t.Errorf("NewExporter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
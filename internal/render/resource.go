@@ -1,30 +1,83 @@
 package render
 
 import (
+	"maps"
+	"slices"
+	"strings"
+
 	"github.com/olekukonko/tablewriter"
+	"github.com/srz-zumix/gali/internal/gcalendar"
 	admdir "google.golang.org/api/admin/directory/v1"
 )
 
-func (r *Renderer) RenderCalendarResource(resources []*admdir.CalendarResource) {
-	if r.exporter != nil {
-		r.exporter.Export(resources)
-		return
+type CalendarResourceFieldGetter func(r *admdir.CalendarResource) string
+
+type CalendarResourceFieldGetters struct {
+	Func map[string]CalendarResourceFieldGetter
+}
+
+func NewCalendarResourceFieldGetters() *CalendarResourceFieldGetters {
+	return &CalendarResourceFieldGetters{
+		Func: map[string]CalendarResourceFieldGetter{
+			"NAME":        func(r *admdir.CalendarResource) string { return r.ResourceName },
+			"EMAIL":       func(r *admdir.CalendarResource) string { return r.ResourceEmail },
+			"BUILDING_ID": func(r *admdir.CalendarResource) string { return r.BuildingId },
+			"DESCRIPTION": func(r *admdir.CalendarResource) string { return r.UserVisibleDescription },
+			"ID":          func(r *admdir.CalendarResource) string { return r.ResourceId },
+			"TYPE":        func(r *admdir.CalendarResource) string { return r.ResourceType },
+			"CATEGORY":    func(r *admdir.CalendarResource) string { return r.ResourceCategory },
+			"CAPACITY": func(r *admdir.CalendarResource) string {
+				if r.Capacity == 0 {
+					return ""
+				}
+				return toString(r.Capacity)
+			},
+			"FLOOR":         func(r *admdir.CalendarResource) string { return r.FloorName },
+			"FLOOR_SECTION": func(r *admdir.CalendarResource) string { return r.FloorSection },
+			"FEATURES": func(r *admdir.CalendarResource) string {
+				return strings.Join(gcalendar.GetCalendarResourceFeatures(r), ", ")
+			},
+		},
 	}
+}
 
-	table := tablewriter.NewWriter(r.IO.Out)
-	table.SetHeader([]string{"Name", "Email", "Building ID", "Description"})
-	table.SetAutoWrapText(false)
-	table.SetRowLine(true)
+func (g *CalendarResourceFieldGetters) Names() []string {
+	return slices.Sorted(maps.Keys(g.Func))
+}
 
+func (g *CalendarResourceFieldGetters) HasField(field string) bool {
+	_, ok := g.Func[strings.ReplaceAll(strings.ToUpper(field), " ", "_")]
+	return ok
+}
+
+func (g *CalendarResourceFieldGetters) GetField(r *admdir.CalendarResource, field string) string {
+	field = strings.ReplaceAll(strings.ToUpper(field), " ", "_")
+	if getter, ok := g.Func[field]; ok {
+		return getter(r)
+	}
+	return ""
+}
+
+func (r *Renderer) RenderCalendarResources(resources []*admdir.CalendarResource, header []string) {
+	if r.exportData(resources) {
+		return
+	}
+	getter := NewCalendarResourceFieldGetters()
+	validateColumns(header, getter)
+	rows := make([][]string, 0, len(resources))
 	for _, resource := range resources {
-		row := []string{
-			resource.ResourceName,
-			resource.ResourceEmail,
-			resource.BuildingId,
-			resource.UserVisibleDescription,
+		row := make([]string, len(header))
+		for i, h := range header {
+			row[i] = getter.GetField(resource, h)
 		}
-		table.Append(row)
+		rows = append(rows, row)
 	}
+	r.renderTable(header, rows, func(table *tablewriter.Table) {
+		table.SetAutoWrapText(false)
+		table.SetRowLine(true)
+	})
+}
 
-	table.Render()
+func (r *Renderer) RenderCalendarResource(resources []*admdir.CalendarResource) {
+	r.RenderCalendarResources(resources, r.columnsOr("Name", "Email", "Building ID", "Description"))
 }
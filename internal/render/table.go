@@ -0,0 +1,127 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// TableExporter is an exporter that writes the same rows and columns as the table view
+type TableExporter interface {
+	Exporter
+	ExportTable(w io.Writer, header []string, rows [][]string)
+}
+
+type CSVExporter struct {
+	Comma rune
+}
+
+func NewCSVExporter() *CSVExporter {
+	return &CSVExporter{Comma: ','}
+}
+
+func NewTSVExporter() *CSVExporter {
+	return &CSVExporter{Comma: '\t'}
+}
+
+func (c *CSVExporter) Export(data any) {
+	log.Fatalf("Delimited output is not supported for %T", data)
+}
+
+func (c *CSVExporter) ExportTable(w io.Writer, header []string, rows [][]string) {
+	writer := csv.NewWriter(w)
+	writer.Comma = c.Comma
+	if err := writer.Write(header); err != nil {
+		log.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		log.Fatalf("Failed to write rows: %v", err)
+	}
+}
+
+type MarkdownExporter struct{}
+
+func (m *MarkdownExporter) Export(data any) {
+	log.Fatalf("Markdown output is not supported for %T", data)
+}
+
+func (m *MarkdownExporter) ExportTable(w io.Writer, header []string, rows [][]string) {
+	escape := func(s string) string {
+		s = strings.ReplaceAll(s, "|", "\\|")
+		s = strings.ReplaceAll(s, "\r\n", "<br>")
+		return strings.ReplaceAll(s, "\n", "<br>")
+	}
+	writeRow := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = escape(c)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | ")); err != nil {
+			log.Fatalf("Failed to write markdown: %v", err)
+		}
+	}
+	writeRow(header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+	for _, row := range rows {
+		writeRow(row)
+	}
+}
+
+// exportData passes data to a non-table exporter and reports whether it did
+func (r *Renderer) exportData(data any) bool {
+	if r.exporter == nil {
+		return false
+	}
+	if _, ok := r.exporter.(TableExporter); ok {
+		return false
+	}
+	if exporter, ok := r.exporter.(WriterExporter); ok {
+		exporter.ExportTo(r.IO.Out, data)
+		return true
+	}
+	r.exporter.Export(data)
+	return true
+}
+
+// renderTable writes rows with the table exporter, or as a text table
+func (r *Renderer) renderTable(header []string, rows [][]string, options ...func(*tablewriter.Table)) {
+	if exporter, ok := r.exporter.(TableExporter); ok {
+		exporter.ExportTable(r.IO.Out, header, rows)
+		return
+	}
+	table := r.newTableWriter(header)
+	for _, option := range options {
+		option(table)
+	}
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+type fieldNames interface {
+	Names() []string
+	HasField(field string) bool
+}
+
+func validateColumns(columns []string, getter fieldNames) {
+	for _, c := range columns {
+		if !getter.HasField(c) {
+			log.Fatalf("Unknown column: %s (available: %s)", c, strings.Join(getter.Names(), ", "))
+		}
+	}
+}
+
+// columnsOr returns the user selected columns, or defaults if none
+func (r *Renderer) columnsOr(defaults ...string) []string {
+	if len(r.Columns) > 0 {
+		return r.Columns
+	}
+	return defaults
+}
@@ -0,0 +1,159 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cli/cli/pkg/text"
+	"github.com/mgutz/ansi"
+	"github.com/srz-zumix/gali/internal/parser"
+	"google.golang.org/api/calendar/v3"
+)
+
+// TemplateExporter executes a Go text/template with the rendered data
+type TemplateExporter struct {
+	Template *template.Template
+}
+
+func NewTemplateExporter(tpl string, colorEnabled bool) (*TemplateExporter, error) {
+	t, err := template.New("").Funcs(templateFuncs(colorEnabled)).Parse(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse template: %w", err)
+	}
+	return &TemplateExporter{Template: t}, nil
+}
+
+func (e *TemplateExporter) Export(data any) {
+	e.ExportTo(os.Stdout, data)
+}
+
+// ExportTo executes the template with data into w (the output of the renderer)
+func (e *TemplateExporter) ExportTo(w io.Writer, data any) {
+	if err := e.Template.Execute(w, data); err != nil {
+		log.Fatalf("Failed to execute template: %v", err)
+	}
+}
+
+// templateTime converts a RFC3339/YYYY-MM-DD string, time.Time or *calendar.EventDateTime to time.Time
+func templateTime(input any) (time.Time, error) {
+	switch v := input.(type) {
+	case time.Time:
+		return v, nil
+	case *calendar.EventDateTime:
+		if v == nil {
+			return time.Time{}, fmt.Errorf("missing date time")
+		}
+		if v.DateTime != "" {
+			return time.Parse(time.RFC3339, v.DateTime)
+		}
+		return time.ParseInLocation("2006-01-02", v.Date, parser.GetLocation())
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		return time.ParseInLocation("2006-01-02", v, parser.GetLocation())
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T to time", input)
+}
+
+func templateDuration(input any) (time.Duration, error) {
+	switch v := input.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		return time.ParseDuration(v)
+	}
+	return 0, fmt.Errorf("cannot convert %T to duration", input)
+}
+
+func templateFuncs(colorEnabled bool) template.FuncMap {
+	funcs := template.FuncMap{
+		"time": templateTime,
+		"timefmt": func(layout string, input any) (string, error) {
+			t, err := templateTime(input)
+			if err != nil {
+				return "", err
+			}
+			return t.Format(layout), nil
+		},
+		"timein": func(zone string, input any) (time.Time, error) {
+			t, err := templateTime(input)
+			if err != nil {
+				return time.Time{}, err
+			}
+			loc, err := time.LoadLocation(zone)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return t.In(loc), nil
+		},
+		"timeadd": func(d any, input any) (time.Time, error) {
+			duration, err := templateDuration(d)
+			if err != nil {
+				return time.Time{}, err
+			}
+			t, err := templateTime(input)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return t.Add(duration), nil
+		},
+		"duration": func(start, end any) (time.Duration, error) {
+			s, err := templateTime(start)
+			if err != nil {
+				return 0, err
+			}
+			e, err := templateTime(end)
+			if err != nil {
+				return 0, err
+			}
+			return e.Sub(s), nil
+		},
+		"durationfmt": func(d any) (string, error) {
+			duration, err := templateDuration(d)
+			if err != nil {
+				return "", err
+			}
+			return formatDuration(duration), nil
+		},
+		"minutes": func(d any) (int, error) {
+			duration, err := templateDuration(d)
+			if err != nil {
+				return 0, err
+			}
+			return int(duration / time.Minute), nil
+		},
+		"truncate": func(width int, s string) string {
+			return text.Truncate(width, s)
+		},
+		"color": func(style string, s string) string {
+			return ansi.Color(s, style)
+		},
+		"autocolor": func(style string, s string) string {
+			return ansi.Color(s, style)
+		},
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"jsonpretty": func(v any) (string, error) {
+			b, err := json.MarshalIndent(v, "", "  ")
+			return string(b), err
+		},
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+	if !colorEnabled {
+		funcs["autocolor"] = func(style string, s string) string {
+			return s
+		}
+	}
+	return funcs
+}
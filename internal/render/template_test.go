@@ -0,0 +1,22 @@
+package render
+
+import (
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestTemplateExporterWritesToRendererOutput(t *testing.T) {
+	exporter, err := NewTemplateExporter("{{range .Items}}{{.Summary}}\n{{end}}", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewStringRenderer()
+	r.Renderer.SetExporter(exporter)
+	r.Renderer.RenderEventsDefault(&calendar.Events{
+		Items: []*calendar.Event{{Summary: "Standup"}, {Summary: "Review"}},
+	})
+	if got, want := r.Stdout.String(), "Standup\nReview\n"; got != want {
+		t.Errorf("renderer output = %q, want %q", got, want)
+	}
+}